@@ -0,0 +1,95 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+func (m *Vermig) AssertVersion(ctx context.Context, expected string) error {
+	pv, parseVersionErr := parseVersion(expected)
+	if parseVersionErr != nil {
+		return fmt.Errorf("parse version failed: %w", parseVersionErr)
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	current, currentVersionErr := highestVersion(
+		migrations, func(Migration) bool {
+			return true
+		},
+	)
+	if currentVersionErr != nil {
+		return fmt.Errorf("get current version failed: %w", currentVersionErr)
+	}
+	applied := make(map[string]struct{}, len(migrations))
+	var extra []string
+	for _, migration := range migrations {
+		applied[migration.key()] = struct{}{}
+		version, parseErr := parseVersion(migration.Version)
+		if parseErr != nil {
+			return fmt.Errorf("parse version failed: %w", parseErr)
+		}
+		if version.GreaterThan(pv) {
+			extra = append(extra, migration.Scope+"/"+migration.Name)
+		}
+	}
+	var missing []string
+	for _, file := range m.files {
+		if file.Version.GreaterThan(pv) || (m.skipPredicate != nil && m.skipPredicate(file)) {
+			continue
+		}
+		if _, exists := applied[file.key()]; !exists {
+			missing = append(missing, file.Scope+"/"+file.Name)
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 && current.Equal(pv) {
+		return nil
+	}
+	details := fmt.Sprintf("expected %s, applied %s", pv, current)
+	if len(missing) > 0 {
+		details += "; missing: " + strings.Join(missing, ", ")
+	}
+	if len(extra) > 0 {
+		details += "; extra: " + strings.Join(extra, ", ")
+	}
+	return fmt.Errorf("%w: %s", ErrVersionMismatch, details)
+}
+
+func (m *Vermig) Check(ctx context.Context) error {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	latest := semver.MustParse("0.0.0")
+	for _, file := range m.files {
+		if file.Version.GreaterThan(latest) {
+			latest = file.Version
+		}
+	}
+	var ahead []string
+	for _, migration := range migrations {
+		version, parseErr := parseVersion(migration.Version)
+		if parseErr != nil {
+			return fmt.Errorf("parse version failed: %w", parseErr)
+		}
+		if version.GreaterThan(latest) {
+			ahead = append(ahead, migration.Scope+"/"+migration.Name)
+		}
+	}
+	if len(ahead) > 0 {
+		return fmt.Errorf("%w: latest file is %s; applied: %s", ErrDatabaseAhead, latest, strings.Join(ahead, ", "))
+	}
+	return nil
+}
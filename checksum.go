@@ -3,16 +3,109 @@ package vermig
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
+type ChecksumNormalization int
+
+const (
+	ChecksumNormalizeNone ChecksumNormalization = iota
+	ChecksumNormalizeWhitespace
+	ChecksumNormalizeComments
+)
+
+var checksumPrefixes = map[ChecksumNormalization]string{
+	ChecksumNormalizeNone:       "v2:",
+	ChecksumNormalizeWhitespace: "v2w:",
+	ChecksumNormalizeComments:   "v2c:",
+}
+
+func fileChecksum(file File, normalization ChecksumNormalization) string {
+	var buf bytes.Buffer
+	for _, value := range []string{
+		file.Scope,
+		file.Version.String(),
+		strconv.Itoa(file.Step),
+		normalizeChecksumSQL(file.Up, normalization),
+		normalizeChecksumSQL(file.Down, normalization),
+	} {
+		_ = binary.Write(&buf, binary.BigEndian, uint64(len(value)))
+		buf.WriteString(value)
+	}
+	return fmt.Sprintf("%s%x", checksumPrefixes[normalization], sha256.Sum256(buf.Bytes()))
+}
+
+func checksumMatches(file File, storedChecksum string) bool {
+	for normalization, prefix := range checksumPrefixes {
+		if strings.HasPrefix(storedChecksum, prefix) {
+			return fileChecksum(file, normalization) == storedChecksum
+		}
+	}
+	return createChecksum(file.Up, file.Down) == storedChecksum
+}
+
+func normalizeChecksumSQL(query string, normalization ChecksumNormalization) string {
+	query = normalizeChecksumValue(query)
+	if normalization == ChecksumNormalizeNone {
+		return query
+	}
+	var sb, code strings.Builder
+	flush := func() {
+		sb.WriteString(collapseWhitespace(code.String()))
+		code.Reset()
+	}
+	for _, segment := range scanSQL(query) {
+		switch {
+		case segment.kind == segmentCode:
+			code.WriteString(segment.text)
+		case segment.kind == segmentComment && normalization == ChecksumNormalizeComments && !isDirectiveComment(segment.text):
+			code.WriteString(" ")
+		default:
+			flush()
+			sb.WriteString(segment.text)
+		}
+	}
+	flush()
+	return strings.TrimSpace(sb.String())
+}
+
+func collapseWhitespace(value string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range value {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	if space {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+func isDirectiveComment(comment string) bool {
+	return strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(comment, "--")), directivePrefix)
+}
+
+func normalizeChecksumValue(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.TrimSpace(value)
+}
+
 func createChecksum(values ...string) string {
 	var buf bytes.Buffer
 	for _, value := range values {
-		value = strings.ReplaceAll(value, "\r\n", "\n")
-		value = strings.TrimSpace(value)
-		buf.WriteString(value)
+		buf.WriteString(normalizeChecksumValue(value))
 	}
 	return fmt.Sprintf("%x", sha256.Sum256(buf.Bytes()))
 }
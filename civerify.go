@@ -0,0 +1,185 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+const schemaSnapshotQuery = `WITH excluded AS (
+    SELECT oid FROM pg_class WHERE oid = to_regclass($1) OR oid = to_regclass($2)
+), relations AS (
+    SELECT c.oid, n.nspname, c.relname, c.relkind
+    FROM pg_class c
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    WHERE n.nspname <> 'information_schema' AND n.nspname NOT LIKE 'pg\_%'
+    AND c.oid NOT IN (SELECT oid FROM excluded)
+    AND NOT EXISTS (SELECT FROM pg_index i WHERE i.indexrelid = c.oid AND i.indrelid IN (SELECT oid FROM excluded))
+    AND NOT EXISTS (
+        SELECT FROM pg_depend d
+        WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.refobjid IN (SELECT oid FROM excluded)
+    )
+)
+SELECT 'schema ' || nspname FROM pg_namespace
+WHERE nspname <> 'information_schema' AND nspname NOT LIKE 'pg\_%'
+UNION ALL
+SELECT 'relation ' || nspname || '.' || relname || ' (' || relkind || ')' FROM relations
+UNION ALL
+SELECT 'column ' || r.nspname || '.' || r.relname || '.' || a.attname || ' ' || format_type(a.atttypid, a.atttypmod)
+FROM relations r
+JOIN pg_attribute a ON a.attrelid = r.oid
+WHERE a.attnum > 0 AND NOT a.attisdropped
+UNION ALL
+SELECT 'function ' || n.nspname || '.' || p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')'
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname <> 'information_schema' AND n.nspname NOT LIKE 'pg\_%'
+UNION ALL
+SELECT 'type ' || n.nspname || '.' || t.typname
+FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+WHERE t.typtype IN ('e', 'd') AND n.nspname <> 'information_schema' AND n.nspname NOT LIKE 'pg\_%'
+ORDER BY 1;`
+
+func (m *Vermig) CIVerify(ctx context.Context) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	if !m.allowDowngrade {
+		return ErrDowngradeNotAllowed
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	if len(m.files) == 0 {
+		return ErrNoMigrationsFound
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	if len(migrations) > 0 {
+		return fmt.Errorf("%w: database already has %d applied migrations", ErrCIVerifyFailed, len(migrations))
+	}
+	latest := m.latestVersion()
+	var versions []*semver.Version
+	for _, file := range m.files {
+		if file.Version.GreaterThan(latest) {
+			continue
+		}
+		known := false
+		for _, version := range versions {
+			known = known || version.Equal(file.Version)
+		}
+		if !known {
+			versions = append(versions, file.Version)
+		}
+	}
+	sort.Slice(
+		versions, func(i, j int) bool {
+			return versions[i].LessThan(versions[j])
+		},
+	)
+	base, snapshotErr := m.schemaSnapshot(ctx)
+	if snapshotErr != nil {
+		return snapshotErr
+	}
+	snapshots := make([][]string, len(versions))
+	for i, version := range versions {
+		if err := m.migrateTo(ctx, version.String(), nil); err != nil {
+			return fmt.Errorf("%w: apply %s failed: %w", ErrCIVerifyFailed, version, err)
+		}
+		if snapshots[i], snapshotErr = m.schemaSnapshot(ctx); snapshotErr != nil {
+			return snapshotErr
+		}
+	}
+	var issues []string
+	for i := len(versions) - 1; i >= 0; i-- {
+		expected := base
+		var revertErr error
+		if i > 0 {
+			expected = snapshots[i-1]
+			revertErr = m.migrateTo(ctx, versions[i-1].String(), nil)
+		} else {
+			revertErr = m.reset(ctx)
+		}
+		if revertErr != nil {
+			issues = append(issues, fmt.Sprintf("%s: down failed: %v", m.versionFiles(versions[i]), revertErr))
+			return fmt.Errorf("%w:\n%s", ErrCIVerifyFailed, strings.Join(issues, "\n"))
+		}
+		reverted, revertedSnapshotErr := m.schemaSnapshot(ctx)
+		if revertedSnapshotErr != nil {
+			return revertedSnapshotErr
+		}
+		if residue := snapshotDiff(expected, reverted); residue != "" {
+			issues = append(issues, fmt.Sprintf("%s: down left residue: %s", m.versionFiles(versions[i]), residue))
+		}
+	}
+	if err := m.migrateTo(ctx, latest.String(), nil); err != nil {
+		return fmt.Errorf("%w: re-apply failed: %w", ErrCIVerifyFailed, err)
+	}
+	reapplied, reappliedSnapshotErr := m.schemaSnapshot(ctx)
+	if reappliedSnapshotErr != nil {
+		return reappliedSnapshotErr
+	}
+	if diff := snapshotDiff(snapshots[len(snapshots)-1], reapplied); diff != "" {
+		issues = append(issues, "re-apply produced a different schema: "+diff)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrCIVerifyFailed, strings.Join(issues, "\n"))
+	}
+	m.logf(ctx, "migrator status: round trip verified ✅")
+	return nil
+}
+
+func (m *Vermig) schemaSnapshot(ctx context.Context) ([]string, error) {
+	var objects []string
+	if err := pgxscan.Select(ctx, m.db, &objects, schemaSnapshotQuery, m.table(), m.versionTableName()); err != nil {
+		return nil, fmt.Errorf("snapshot schema failed: %w", err)
+	}
+	return objects, nil
+}
+
+func (m *Vermig) versionFiles(version *semver.Version) string {
+	var names []string
+	for _, file := range m.files {
+		if file.Version.Equal(version) {
+			names = append(names, file.Scope+"/"+file.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func snapshotDiff(expected, actual []string) string {
+	counts := make(map[string]int, len(expected))
+	for _, object := range expected {
+		counts[object]++
+	}
+	for _, object := range actual {
+		counts[object]--
+	}
+	var diff []string
+	for _, object := range actual {
+		if counts[object] < 0 {
+			diff = append(diff, "+"+object)
+			counts[object]++
+		}
+	}
+	for _, object := range expected {
+		if counts[object] > 0 {
+			diff = append(diff, "-"+object)
+			counts[object]--
+		}
+	}
+	return strings.Join(diff, ", ")
+}
@@ -0,0 +1,116 @@
+package vermig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultNameColumnLength = 255
+
+func (m *Vermig) extraColumnNames() []string {
+	names := make([]string, 0, len(m.extraColumns))
+	for name := range m.extraColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Vermig) extraValues(file File) map[string]any {
+	values := make(map[string]any, len(m.extraColumns))
+	for name, value := range m.extraColumns {
+		v := value(file)
+		switch v := v.(type) {
+		case nil:
+			values[name] = nil
+		case string:
+			values[name] = v
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
+
+func (m *Vermig) columnLength() int {
+	if m.nameColumnLength > 0 {
+		return m.nameColumnLength
+	}
+	return defaultNameColumnLength
+}
+
+func (m *Vermig) checkNameLength(file File) error {
+	length := m.columnLength()
+	if utf8.RuneCountInString(file.Name) > length {
+		return fmt.Errorf("%w: name of %s exceeds %d characters", ErrNameTooLong, file.UpPath, length)
+	}
+	if utf8.RuneCountInString(file.Scope) > length {
+		return fmt.Errorf("%w: scope of %s exceeds %d characters", ErrNameTooLong, file.UpPath, length)
+	}
+	return nil
+}
+
+func (m *Vermig) col(name string) string {
+	if mapped, exists := m.columnMapping[name]; exists {
+		return pgx.Identifier{mapped}.Sanitize()
+	}
+	return name
+}
+
+func (m *Vermig) physicalColumn(name string) string {
+	if mapped, exists := m.columnMapping[name]; exists {
+		return mapped
+	}
+	return name
+}
+
+func (m *Vermig) selectCols(names ...string) []string {
+	columns := make([]string, len(names))
+	for i, name := range names {
+		column := m.col(name)
+		if name == "id" {
+			column += "::text"
+		}
+		if column != name {
+			column += " AS " + name
+		}
+		columns[i] = column
+	}
+	return columns
+}
+
+func (m *Vermig) uniqueColumns() string {
+	columns := make([]string, 0, 7)
+	for _, column := range []string{"scope", "version", "major", "minor", "patch", "prerelease", "step"} {
+		columns = append(columns, m.col(column))
+	}
+	return strings.Join(columns, ", ")
+}
+
+func (m *Vermig) checkColumnMapping() error {
+	known := make(map[string]struct{}, len(requiredColumns))
+	for _, column := range requiredColumns {
+		known[column] = struct{}{}
+	}
+	for logical, physical := range m.columnMapping {
+		if _, exists := known[logical]; !exists {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidColumnMapping, logical)
+		}
+		if physical == "" {
+			return fmt.Errorf("%w: empty name for column %q", ErrInvalidColumnMapping, logical)
+		}
+	}
+	physicals := make(map[string]string, len(requiredColumns))
+	for _, logical := range requiredColumns {
+		physical := m.physicalColumn(logical)
+		if other, exists := physicals[physical]; exists {
+			return fmt.Errorf("%w: %q and %q both map to %q", ErrInvalidColumnMapping, other, logical, physical)
+		}
+		physicals[physical] = logical
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package vermig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		file    File
+		wantErr bool
+	}{
+		{
+			name: "multibyte name at the default limit",
+			file: File{Name: strings.Repeat("ž", 255), Scope: "app"},
+		},
+		{
+			name:    "multibyte name above the default limit",
+			file:    File{Name: strings.Repeat("ž", 256), Scope: "app"},
+			wantErr: true,
+		},
+		{
+			name:   "multibyte scope at a custom limit",
+			length: 10,
+			file:   File{Name: "1.0.0.sql", Scope: strings.Repeat("日", 10)},
+		},
+		{
+			name:    "multibyte scope above a custom limit",
+			length:  10,
+			file:    File{Name: "1.0.0.sql", Scope: strings.Repeat("日", 11)},
+			wantErr: true,
+		},
+		{
+			name:   "emoji count as one character",
+			length: 300,
+			file:   File{Name: strings.Repeat("🚀", 300), Scope: "app"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				m := newVermig(WithNameColumnLength(test.length))
+				err := m.checkNameLength(test.file)
+				if test.wantErr != errors.Is(err, ErrNameTooLong) {
+					t.Errorf("got %v, want error %t", err, test.wantErr)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+var requiredColumns = []string{
+	"id", "name", "version", "major", "minor", "patch", "prerelease", "step", "scope", "up", "down", "checksum",
+	"source_path", "tags", "created_at",
+}
+
+func (m *Vermig) tableColumns(ctx context.Context, db DB) (map[string]bool, error) {
+	var columns []struct {
+		Name    string `db:"attname"`
+		NotNull bool   `db:"attnotnull"`
+	}
+	if err := pgxscan.Select(
+		ctx, db, &columns,
+		"SELECT attname, attnotnull FROM pg_attribute WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped",
+		m.table(),
+	); err != nil {
+		return nil, fmt.Errorf("select migrations table columns failed: %w", err)
+	}
+	notNull := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		notNull[column.Name] = column.NotNull
+	}
+	return notNull, nil
+}
+
+func (m *Vermig) missingColumns(columns map[string]bool) []string {
+	var missing []string
+	for _, column := range requiredColumns {
+		if _, exists := columns[m.physicalColumn(column)]; !exists {
+			missing = append(missing, m.physicalColumn(column))
+		}
+	}
+	for _, column := range m.extraColumnNames() {
+		if _, exists := columns[column]; !exists {
+			missing = append(missing, column)
+		}
+	}
+	return missing
+}
+
+func (m *Vermig) checkTableColumns(ctx context.Context) error {
+	columns, tableColumnsErr := m.tableColumns(ctx, m.db)
+	if tableColumnsErr != nil {
+		return tableColumnsErr
+	}
+	if missing := m.missingColumns(columns); len(missing) > 0 {
+		return fmt.Errorf("%w: missing columns %s", ErrIncompatibleTable, strings.Join(missing, ", "))
+	}
+	if columns[m.physicalColumn("version")] {
+		return fmt.Errorf("%w: column %s must be nullable", ErrIncompatibleTable, m.physicalColumn("version"))
+	}
+	return m.readIDType(ctx, m.db)
+}
+
+func (m *Vermig) readIDType(ctx context.Context, db DB) error {
+	if err := db.QueryRow(
+		ctx,
+		"SELECT format_type(atttypid, atttypmod) FROM pg_attribute WHERE attrelid = to_regclass($1) AND attname = $2",
+		m.table(), m.physicalColumn("id"),
+	).Scan(&m.idType); err != nil {
+		return fmt.Errorf("select migrations id type failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Vermig) diagnoseTableErr(ctx context.Context, db DB, err error) error {
+	if _, inTx := db.(pgx.Tx); inTx {
+		return err
+	}
+	columns, checkErr := m.tableColumns(ctx, db)
+	if checkErr != nil {
+		return err
+	}
+	missing := m.missingColumns(columns)
+	if len(missing) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: missing columns %s: %w", ErrIncompatibleTable, strings.Join(missing, ", "), err)
+}
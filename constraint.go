@@ -0,0 +1,78 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) EnsureConstraints(ctx context.Context) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			if err := m.lockTable(ctx, tx); err != nil {
+				return fmt.Errorf("lock migrations table failed: %w", err)
+			}
+			return m.ensureConstraints(ctx, tx)
+		},
+	); err != nil {
+		return fmt.Errorf("ensure constraints failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Vermig) ensureConstraints(ctx context.Context, db DB) error {
+	table := m.table()
+	var exists bool
+	if err := pgxscan.Get(
+		ctx, db, &exists, `SELECT EXISTS (
+    SELECT FROM pg_constraint c
+    JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY (c.conkey)
+    WHERE c.conrelid = to_regclass($1) AND c.conname = 'uq_scope_version' AND a.attname = $2
+);`, table, m.physicalColumn("step"),
+	); err != nil {
+		return fmt.Errorf("check unique constraint failed: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	var duplicates []struct {
+		Scope   string
+		Version string
+		Step    int
+		Count   int
+	}
+	if err := pgxscan.Select(
+		ctx, db, &duplicates, `SELECT `+strings.Join(m.selectCols("scope", "version", "step"), ", ")+`, count(*) AS count
+FROM `+table+`
+WHERE `+m.col("version")+` IS NOT NULL
+GROUP BY `+m.uniqueColumns()+`
+HAVING count(*) > 1
+ORDER BY 1, 2, 3;`,
+	); err != nil {
+		return fmt.Errorf("find duplicate migrations failed: %w", err)
+	}
+	if len(duplicates) > 0 {
+		keys := make([]string, len(duplicates))
+		for i, duplicate := range duplicates {
+			keys[i] = fmt.Sprintf("%s (%d rows)", migrationKey(duplicate.Scope, duplicate.Version, duplicate.Step), duplicate.Count)
+		}
+		return fmt.Errorf("%w: %s", ErrDuplicateMigrations, strings.Join(keys, ", "))
+	}
+	query := `ALTER TABLE ` + table + ` DROP CONSTRAINT IF EXISTS uq_scope_version;
+ALTER TABLE ` + table + ` ADD CONSTRAINT uq_scope_version UNIQUE (` + m.uniqueColumns() + `);`
+	if _, err := db.Exec(ctx, query); err != nil {
+		return fmt.Errorf("add unique constraint failed: %w", err)
+	}
+	return nil
+}
@@ -5,11 +5,23 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// DB is the connection vermig runs on, passed with WithDB. It is satisfied by
+// *pgxpool.Pool, *pgx.Conn and pgx.Tx, so a tracing or metrics decorator only
+// has to wrap these four methods. WithTxOptions additionally needs
+// BeginTx(ctx, pgx.TxOptions) (pgx.Tx, error).
 type DB interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Exec(ctx context.Context, sql string, arguments ...any) (commandTag pgconn.CommandTag, err error)
 	Begin(ctx context.Context) (pgx.Tx, error)
 }
+
+var (
+	_ DB         = (*pgxpool.Pool)(nil)
+	_ DB         = (*pgx.Conn)(nil)
+	_ DB         = pgx.Tx(nil)
+	_ txBeginner = (*pgxpool.Pool)(nil)
+)
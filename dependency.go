@@ -0,0 +1,118 @@
+package vermig
+
+import (
+	"fmt"
+	"strings"
+)
+
+const requiresDirective = "requires"
+
+type dependency struct {
+	scope   string
+	version string
+	step    int
+}
+
+func (d dependency) key() string {
+	return migrationKey(d.scope, d.version, d.step)
+}
+
+func parseDependency(reference string) (dependency, error) {
+	separator := strings.LastIndex(reference, "/")
+	if separator < 0 {
+		return dependency{}, fmt.Errorf("invalid dependency %q: expected scope/version", reference)
+	}
+	pv, step, parseVersionErr := parseFileVersion(reference[separator+1:])
+	if parseVersionErr != nil {
+		return dependency{}, fmt.Errorf("invalid dependency %q: %w", reference, parseVersionErr)
+	}
+	return dependency{scope: reference[:separator], version: pv.String(), step: step}, nil
+}
+
+func (m *Vermig) orderDependencies() error {
+	index := make(map[string]int, len(m.files))
+	for i, file := range m.files {
+		index[file.key()] = i
+	}
+	dependencies := make([][]int, len(m.files))
+	hasDependencies := false
+	for i, file := range m.files {
+		for _, reference := range file.Directives[requiresDirective] {
+			d, parseErr := parseDependency(reference)
+			if parseErr != nil {
+				return &ParseError{Path: file.UpPath, Err: parseErr}
+			}
+			j, exists := index[d.key()]
+			if !exists {
+				continue
+			}
+			dependencies[i] = append(dependencies[i], j)
+			hasDependencies = true
+		}
+	}
+	if !hasDependencies {
+		return nil
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make([]int, len(m.files))
+	ordered := make([]File, 0, len(m.files))
+	var stack []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch states[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := []string{}
+			for k := len(stack) - 1; k >= 0; k-- {
+				cycle = append([]string{m.files[stack[k]].Scope + "/" + m.files[stack[k]].Name}, cycle...)
+				if stack[k] == i {
+					break
+				}
+			}
+			cycle = append(cycle, m.files[i].Scope+"/"+m.files[i].Name)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+		states[i] = visiting
+		stack = append(stack, i)
+		for _, j := range dependencies[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[i] = visited
+		ordered = append(ordered, m.files[i])
+		return nil
+	}
+	for i := range m.files {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	m.files = ordered
+	return nil
+}
+
+func checkDependencies(applied, planned map[string]struct{}, file File) error {
+	for _, reference := range file.Directives[requiresDirective] {
+		d, parseErr := parseDependency(reference)
+		if parseErr != nil {
+			return &ParseError{Path: file.UpPath, Err: parseErr}
+		}
+		if _, exists := planned[d.key()]; exists {
+			continue
+		}
+		if _, exists := applied[d.key()]; !exists {
+			return fmt.Errorf(
+				"%w: %s/%s requires %s, which is neither applied nor planned", ErrMissingDependency, file.Scope,
+				file.Name, reference,
+			)
+		}
+	}
+	return nil
+}
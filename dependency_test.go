@@ -0,0 +1,22 @@
+package vermig
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderDependenciesUncollected(t *testing.T) {
+	m := newVermig()
+	m.files = unitFiles("1.0.0", "1.1.0")
+	m.files[0].Directives = Directives{requiresDirective: {"app/0.9.0"}}
+	if err := m.orderDependencies(); err != nil {
+		t.Fatalf("order dependencies failed: %s", err)
+	}
+	applied := map[string]struct{}{migrationKey("app", "0.9.0", 0): {}}
+	if err := checkDependencies(applied, map[string]struct{}{}, m.files[0]); err != nil {
+		t.Errorf("applied dependency: %s", err)
+	}
+	if err := checkDependencies(map[string]struct{}{}, map[string]struct{}{}, m.files[0]); !errors.Is(err, ErrMissingDependency) {
+		t.Errorf("missing dependency: got %v, want %v", err, ErrMissingDependency)
+	}
+}
@@ -0,0 +1,47 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type allowDestructiveDownKey struct{}
+
+var destructivePattern = regexp.MustCompile(`(?i)\b(DROP\s+TABLE|DROP\s+COLUMN|TRUNCATE)\b`)
+
+func AllowDestructiveDown(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowDestructiveDownKey{}, true)
+}
+
+func (m *Vermig) checkDestructiveDown(ctx context.Context, migrations []Migration) error {
+	if !m.blockDestructiveDown {
+		return nil
+	}
+	if allowed, _ := ctx.Value(allowDestructiveDownKey{}).(bool); allowed {
+		return nil
+	}
+	for _, migration := range migrations {
+		if statement := destructiveStatement(migration.Down); statement != "" {
+			return fmt.Errorf("%w: %s/%s: %s", ErrDestructiveDown, migration.Scope, migration.Name, statement)
+		}
+	}
+	return nil
+}
+
+func destructiveStatement(query string) string {
+	for _, statement := range splitStatements(query) {
+		var code strings.Builder
+		for _, segment := range scanSQL(statement) {
+			if segment.kind == segmentCode {
+				code.WriteString(segment.text)
+			}
+			code.WriteString(" ")
+		}
+		if destructivePattern.MatchString(code.String()) {
+			return statement
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package vermig
+
+import (
+	"strings"
+)
+
+const directivePrefix = "vermig:"
+
+type Directives map[string][]string
+
+func parseDirectives(query string) Directives {
+	directives := make(Directives)
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "--") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if !strings.HasPrefix(line, directivePrefix) {
+			continue
+		}
+		key, value := strings.TrimPrefix(line, directivePrefix), ""
+		if i := strings.IndexAny(key, "= \t"); i >= 0 {
+			key, value = key[:i], strings.TrimSpace(key[i+1:])
+		}
+		directives[key] = append(directives[key], value)
+	}
+	return directives
+}
+
+func (d Directives) Has(key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+func (d Directives) Get(key string) string {
+	if values := d[key]; len(values) > 0 {
+		return values[len(values)-1]
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+)
+
+type DownCheck int
+
+const (
+	DownCheckOff DownCheck = iota
+	DownCheckWarn
+	DownCheckError
+)
+
+func (m *Vermig) checkDownConsistency(ctx context.Context, migrations []Migration) error {
+	if m.downCheck == DownCheckOff {
+		return nil
+	}
+	files := make(map[string]File, len(m.files))
+	for _, file := range m.files {
+		files[file.key()] = file
+	}
+	for _, migration := range migrations {
+		file, exists := files[migration.key()]
+		if !exists || normalizeChecksumValue(file.Down) == normalizeChecksumValue(migration.Down) {
+			continue
+		}
+		if m.downCheck == DownCheckError {
+			return fmt.Errorf("%w: %s/%s", ErrDownMismatch, migration.Scope, migration.Name)
+		}
+		m.logf(ctx, "⚠️ %s/%s: stored down SQL differs from %s", migration.Scope, migration.Name, file.DownPath)
+	}
+	return nil
+}
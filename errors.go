@@ -0,0 +1,74 @@
+package vermig
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDowngradeNotAllowed    = errors.New("downgrade not enabled")
+	ErrOrphanedMigration      = errors.New("applied migration has no file")
+	ErrMigrationsTableMissing = errors.New("migrations table missing")
+	ErrNoPendingMigrations    = errors.New("no pending migrations")
+	ErrMigrationNotFound      = errors.New("migration not found")
+	ErrTxOptionsUnsupported   = errors.New("db does not support transaction options")
+	ErrVersionPinned          = errors.New("version is above the pinned max version")
+	ErrNoMigrationsFound      = errors.New("no migrations found")
+	ErrTenantSchemaRequired   = errors.New("tenant schema required")
+	ErrDestructiveDown        = errors.New("destructive down migration blocked")
+	ErrNameTooLong            = errors.New("migration name too long")
+	ErrMissingDependency      = errors.New("missing migration dependency")
+	ErrDependencyCycle        = errors.New("migration dependency cycle")
+	ErrVersionMismatch        = errors.New("schema version mismatch")
+	ErrDevModeRequired        = errors.New("dev mode required")
+	ErrMigrationReadFailed    = errors.New("read migration file failed")
+	ErrDownMismatch           = errors.New("stored down SQL differs from file")
+	ErrDuplicateMigrations    = errors.New("duplicate migration rows")
+	ErrMissingArg             = errors.New("missing migration argument")
+	ErrIncompatibleTable      = errors.New("migrations table schema incompatible")
+	ErrStateConflict          = errors.New("imported state conflicts with applied migrations")
+	ErrScopeExists            = errors.New("scope already has applied migrations")
+	ErrDownSQLNotStored       = errors.New("down sql not stored")
+	ErrDatabaseAhead          = errors.New("database is ahead of migration files")
+	ErrStopped                = errors.New("migration stopped")
+	ErrMigrationInProgress    = errors.New("migration already in progress on this instance")
+	ErrInvalidColumnMapping   = errors.New("invalid column mapping")
+	ErrCIVerifyFailed         = errors.New("ci verify failed")
+	ErrVersionCollision       = errors.New("migration version collision")
+	ErrOrphanedDownFile       = errors.New("down file has no matching up file")
+	ErrPlanRejected           = errors.New("plan rejected")
+	ErrPlanChanged            = errors.New("plan changed since approval")
+	ErrPostStepFailed         = errors.New("post step failed")
+	ErrOutsideWindow          = errors.New("outside the allowed migration window")
+)
+
+var errAfterCommitFailed = errors.New("after commit hook failed")
+
+type MigrationError struct {
+	Direction Direction
+	Scope     string
+	Name      string
+	Version   string
+	Err       error
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("%s %s/%s: %v", e.Direction, e.Scope, e.Name, e.Err)
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,53 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+)
+
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+type EventType string
+
+const (
+	EventMigrationApplied  EventType = "migration_applied"
+	EventMigrationReverted EventType = "migration_reverted"
+	EventStatementExecuted EventType = "statement_executed"
+	EventRollback          EventType = "rollback"
+)
+
+type Event struct {
+	Type          EventType
+	Direction     Direction
+	Scope         string
+	Name          string
+	Version       string
+	Statement     int
+	Statements    int
+	Err           error
+	CorrelationID string
+}
+
+func (m *Vermig) emit(ctx context.Context, event Event) {
+	if m.eventHandler != nil {
+		event.CorrelationID = correlationID(ctx)
+		m.eventHandler(event)
+	}
+}
+
+func (m *Vermig) emitRollback(ctx context.Context, err error) {
+	event := Event{Type: EventRollback, Err: err}
+	var migrationErr *MigrationError
+	if errors.As(err, &migrationErr) {
+		event.Direction = migrationErr.Direction
+		event.Scope = migrationErr.Scope
+		event.Name = migrationErr.Name
+		event.Version = migrationErr.Version
+	}
+	m.emit(ctx, event)
+}
@@ -0,0 +1,169 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const ifNotExistsOkDirective = "if-not-exists-ok"
+
+var alreadyExistsCodes = map[string]struct{}{
+	"42P06": {},
+	"42P07": {},
+	"42701": {},
+	"42710": {},
+	"42723": {},
+}
+
+type argsKey struct{}
+
+func (m *Vermig) MigrateWithArgs(ctx context.Context, version string, args map[string]any) error {
+	return m.Migrate(context.WithValue(ctx, argsKey{}, args), version)
+}
+
+func (m *Vermig) execQuery(ctx context.Context, tx pgx.Tx, event Event, query string) error {
+	if !m.statementSplitting && !usesNamedArgs(ctx, query) {
+		_, err := tx.Exec(ctx, query)
+		return err
+	}
+	statements := splitStatements(query)
+	for i, statement := range statements {
+		if err := execStatement(ctx, tx, statement); err != nil {
+			return fmt.Errorf("statement %d/%d failed: %w", i+1, len(statements), err)
+		}
+		if !m.statementSplitting {
+			continue
+		}
+		m.logf(ctx, "⏩ %s/%s: statement %d/%d", event.Scope, event.Name, i+1, len(statements))
+		event.Type = EventStatementExecuted
+		event.Statement = i + 1
+		event.Statements = len(statements)
+		m.emit(ctx, event)
+	}
+	return nil
+}
+
+func (m *Vermig) execTolerant(ctx context.Context, tx pgx.Tx, event Event, query string) error {
+	statements := splitStatements(query)
+	for i, statement := range statements {
+		savepoint, beginErr := tx.Begin(ctx)
+		if beginErr != nil {
+			return fmt.Errorf("begin savepoint failed: %w", beginErr)
+		}
+		if execErr := execStatement(ctx, savepoint, statement); execErr != nil {
+			if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+				return errors.Join(
+					fmt.Errorf("rollback savepoint failed: %w", rollbackErr),
+					fmt.Errorf("statement %d/%d failed: %w", i+1, len(statements), execErr),
+				)
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(execErr, &pgErr) {
+				if _, tolerated := alreadyExistsCodes[pgErr.Code]; tolerated {
+					m.logf(ctx, "⏭️ %s/%s: statement %d/%d: %s", event.Scope, event.Name, i+1, len(statements), pgErr.Message)
+					continue
+				}
+			}
+			return fmt.Errorf("statement %d/%d failed: %w", i+1, len(statements), execErr)
+		}
+		if commitErr := savepoint.Commit(ctx); commitErr != nil {
+			return fmt.Errorf("release savepoint failed: %w", commitErr)
+		}
+	}
+	return nil
+}
+
+func execStatement(ctx context.Context, tx pgx.Tx, statement string) error {
+	if !usesNamedArgs(ctx, statement) {
+		_, err := tx.Exec(ctx, statement)
+		return err
+	}
+	args, _ := ctx.Value(argsKey{}).(map[string]any)
+	query, values, bindErr := bindNamedArgs(statement, args)
+	if bindErr != nil {
+		return bindErr
+	}
+	_, err := tx.Exec(ctx, query, values...)
+	return err
+}
+
+func usesNamedArgs(ctx context.Context, query string) bool {
+	if _, withArgs := ctx.Value(argsKey{}).(map[string]any); !withArgs {
+		return false
+	}
+	return len(namedArgs(query)) > 0
+}
+
+func bindNamedArgs(statement string, args map[string]any) (string, []any, error) {
+	positions := make(map[string]int)
+	var values []any
+	var missing []string
+	query := rewriteNamedArgs(
+		statement, func(name string) string {
+			if _, exists := positions[name]; !exists {
+				value, ok := args[name]
+				if !ok {
+					missing = append(missing, "@"+name)
+				}
+				values = append(values, value)
+				positions[name] = len(values)
+			}
+			return "$" + strconv.Itoa(positions[name])
+		},
+	)
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("%w: %s", ErrMissingArg, strings.Join(missing, ", "))
+	}
+	return query, values, nil
+}
+
+func namedArgs(query string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	rewriteNamedArgs(
+		query, func(name string) string {
+			if _, exists := seen[name]; !exists {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+			return "@" + name
+		},
+	)
+	return names
+}
+
+func rewriteNamedArgs(query string, replace func(name string) string) string {
+	var sb strings.Builder
+	for _, segment := range scanSQL(query) {
+		if segment.kind != segmentCode {
+			sb.WriteString(segment.text)
+			continue
+		}
+		text := segment.text
+		start := 0
+		for i := 0; i < len(text); i++ {
+			if text[i] != '@' || (i > 0 && (text[i-1] == '@' || isIdentChar(text[i-1]))) {
+				continue
+			}
+			j := i + 1
+			for j < len(text) && isIdentChar(text[j]) && text[j] != '$' {
+				j++
+			}
+			if j == i+1 || text[i+1] >= '0' && text[i+1] <= '9' {
+				continue
+			}
+			sb.WriteString(text[start:i])
+			sb.WriteString(replace(text[i+1 : j]))
+			start = j
+			i = j - 1
+		}
+		sb.WriteString(text[start:])
+	}
+	return sb.String()
+}
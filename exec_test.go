@@ -0,0 +1,101 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBindNamedArgs(t *testing.T) {
+	args := map[string]any{"email": "a@b.c", "role": "admin", "v": "unused"}
+	tests := []struct {
+		name      string
+		statement string
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "repeated names share a placeholder",
+			statement: "INSERT INTO users (email, role) VALUES (@email, @role) ON CONFLICT (email) DO UPDATE SET email = @email",
+			wantQuery: "INSERT INTO users (email, role) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET email = $1",
+			wantArgs:  []any{"a@b.c", "admin"},
+		},
+		{
+			name:      "dollar-quoted body is left alone",
+			statement: "CREATE FUNCTION f() RETURNS text AS $$ SELECT @v::text $$ LANGUAGE sql; SELECT @email",
+			wantQuery: "CREATE FUNCTION f() RETURNS text AS $$ SELECT @v::text $$ LANGUAGE sql; SELECT $1",
+			wantArgs:  []any{"a@b.c"},
+		},
+		{
+			name:      "tagged dollar quote is left alone",
+			statement: "DO $body$ BEGIN PERFORM @v; END $body$; SELECT @role",
+			wantQuery: "DO $body$ BEGIN PERFORM @v; END $body$; SELECT $1",
+			wantArgs:  []any{"admin"},
+		},
+		{
+			name:      "operators are not parameters",
+			statement: "SELECT * FROM docs WHERE body @@ to_tsquery(@role) AND tags @> ARRAY[@email] AND x@@to_tsquery('a')",
+			wantQuery: "SELECT * FROM docs WHERE body @@ to_tsquery($1) AND tags @> ARRAY[$2] AND x@@to_tsquery('a')",
+			wantArgs:  []any{"admin", "a@b.c"},
+		},
+		{
+			name:      "literals, identifiers and comments are left alone",
+			statement: "SELECT '@v', \"@v\", @role -- @v\n/* @v */",
+			wantQuery: "SELECT '@v', \"@v\", $1 -- @v\n/* @v */",
+			wantArgs:  []any{"admin"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				query, values, err := bindNamedArgs(test.statement, args)
+				if err != nil {
+					t.Fatalf("bind named args failed: %s", err)
+				}
+				if query != test.wantQuery {
+					t.Errorf("query: got %q, want %q", query, test.wantQuery)
+				}
+				if !slices.Equal(values, test.wantArgs) {
+					t.Errorf("args: got %v, want %v", values, test.wantArgs)
+				}
+			},
+		)
+	}
+}
+
+func TestBindNamedArgsMissing(t *testing.T) {
+	_, _, err := bindNamedArgs("SELECT @email, $$ @body $$", map[string]any{})
+	if !errors.Is(err, ErrMissingArg) {
+		t.Fatalf("got %v, want %v", err, ErrMissingArg)
+	}
+	if err.Error() != "missing migration argument: @email" {
+		t.Errorf("got %q", err)
+	}
+}
+
+func TestUsesNamedArgs(t *testing.T) {
+	query := "CREATE VIEW v AS SELECT @x AS y FROM t"
+	if usesNamedArgs(t.Context(), query) {
+		t.Error("without args: @x was taken for a parameter")
+	}
+	withArgs := context.WithValue(t.Context(), argsKey{}, map[string]any{"x": 1})
+	if !usesNamedArgs(withArgs, query) {
+		t.Error("with args: @x was not taken for a parameter")
+	}
+}
+
+func TestMigrateWithoutArgsKeepsAtTokens(t *testing.T) {
+	db := testDB(t)
+	files := fstest.MapFS{
+		"1.0.0_abs_up.sql": &fstest.MapFile{
+			Data: []byte("CREATE TABLE t (x int);\nCREATE VIEW v AS SELECT @x AS y FROM t WHERE ARRAY[x] <@ ARRAY[1, -1];"),
+		},
+		"1.0.0_abs_down.sql": &fstest.MapFile{Data: []byte("DROP VIEW v;\nDROP TABLE t;")},
+	}
+	m := testVermig(t, db, files)
+	if err := m.Migrate(t.Context(), "1.0.0"); err != nil {
+		t.Fatalf("migrate failed: %s", err)
+	}
+}
@@ -5,10 +5,27 @@ import (
 )
 
 type File struct {
-	Priority []int
-	Version  *semver.Version
-	Scope    string
-	Name     string
-	UpPath   string
-	DownPath string
+	Priority   []int
+	Version    *semver.Version
+	Step       int
+	Scope      string
+	Name       string
+	UpPath     string
+	DownPath   string
+	Up         string
+	Down       string
+	Directives Directives
+	downErr    error
+}
+
+func (f File) key() string {
+	return migrationKey(f.Scope, f.Version.String(), f.Step)
+}
+
+func (f File) migrationError(err error) *MigrationError {
+	migrationErr := &MigrationError{Direction: DirectionUp, Scope: f.Scope, Name: f.Name, Err: err}
+	if f.Version != nil {
+		migrationErr.Version = f.Version.String()
+	}
+	return migrationErr
 }
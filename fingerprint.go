@@ -0,0 +1,31 @@
+package vermig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+func (m *Vermig) Fingerprint(ctx context.Context) (string, error) {
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return "", fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	if err := sortMigrationsDesc(migrations); err != nil {
+		return "", fmt.Errorf("sort migrations failed: %w", err)
+	}
+	var buf bytes.Buffer
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		for _, value := range []string{
+			migration.Scope, migration.Version, strconv.Itoa(migration.Step), migration.Checksum,
+		} {
+			_ = binary.Write(&buf, binary.BigEndian, uint64(len(value)))
+			buf.WriteString(value)
+		}
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(buf.Bytes())), nil
+}
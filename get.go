@@ -0,0 +1,66 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) GetMigration(ctx context.Context, scope, version string) (*Migration, error) {
+	pv, step, parseVersionErr := m.parseOrderedVersion(version)
+	if parseVersionErr != nil {
+		return nil, fmt.Errorf("parse version failed: %w", parseVersionErr)
+	}
+	return m.getMigration(ctx, m.db, scope, pv, step)
+}
+
+func (m *Vermig) getMigration(ctx context.Context, db DB, scope string, pv *semver.Version, step int) (*Migration, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select().
+		Columns(m.selectCols(migrationColumns...)...).
+		From(m.table()).
+		Where(squirrel.Eq{m.col("scope"): scope, m.col("version"): pv.String(), m.col("step"): step}).
+		ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create get migration sql failed: %w", createSqlErr)
+	}
+	var migration Migration
+	if err := pgxscan.Get(ctx, db, &migration, sql, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrMigrationNotFound, scope, pv)
+		}
+		return nil, fmt.Errorf("get migration failed: %w", err)
+	}
+	return &migration, nil
+}
+
+func (m *Vermig) IsApplied(ctx context.Context, scope, version string) (bool, error) {
+	pv, step, parseVersionErr := m.parseOrderedVersion(version)
+	if parseVersionErr != nil {
+		return false, fmt.Errorf("parse version failed: %w", parseVersionErr)
+	}
+	exists, existsErr := m.migrationExists(ctx, m.db, scope, pv.String(), step)
+	if existsErr != nil {
+		return false, fmt.Errorf("check migration applied failed: %w", existsErr)
+	}
+	return exists, nil
+}
+
+func (m *Vermig) DownSQL(ctx context.Context, scope, version string) (string, error) {
+	migration, getMigrationErr := m.GetMigration(ctx, scope, version)
+	if getMigrationErr != nil {
+		return "", getMigrationErr
+	}
+	if migration.Down == "" && m.withoutDownSQL {
+		return "", fmt.Errorf("%w: %s/%s", ErrDownSQLNotStored, migration.Scope, migration.Name)
+	}
+	query, transformErr := m.transformSQL(migration.file(), migration.Down)
+	if transformErr != nil {
+		return "", migration.migrationError(transformErr)
+	}
+	return query, nil
+}
@@ -0,0 +1,34 @@
+package vermig
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+func (m *Vermig) GraphDOT() (string, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return "", fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	var sb strings.Builder
+	sb.WriteString("digraph migrations {\n\trankdir=LR;\n")
+	previous := make(map[string]File)
+	for _, file := range m.files {
+		fmt.Fprintf(&sb, "\t%s [label=%s];\n", strconv.Quote(file.key()), strconv.Quote(path.Join(file.Scope, file.Name)))
+		if before, exists := previous[file.Scope]; exists {
+			fmt.Fprintf(&sb, "\t%s -> %s [style=dashed];\n", strconv.Quote(before.key()), strconv.Quote(file.key()))
+		}
+		previous[file.Scope] = file
+		for _, reference := range file.Directives[requiresDirective] {
+			d, parseErr := parseDependency(reference)
+			if parseErr != nil {
+				return "", &ParseError{Path: file.UpPath, Err: parseErr}
+			}
+			fmt.Fprintf(&sb, "\t%s -> %s;\n", strconv.Quote(d.key()), strconv.Quote(file.key()))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
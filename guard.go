@@ -0,0 +1,12 @@
+package vermig
+
+func (m *Vermig) enter() error {
+	if !m.running.CompareAndSwap(false, true) {
+		return ErrMigrationInProgress
+	}
+	return nil
+}
+
+func (m *Vermig) leave() {
+	m.running.Store(false)
+}
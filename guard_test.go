@@ -0,0 +1,83 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type blockingDB struct {
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (db *blockingDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	db.once.Do(
+		func() {
+			close(db.started)
+		},
+	)
+	<-db.release
+	return nil, pgx.ErrTxClosed
+}
+
+func (db *blockingDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func (db *blockingDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, pgx.ErrTxClosed
+}
+
+func (db *blockingDB) Begin(context.Context) (pgx.Tx, error) {
+	return nil, pgx.ErrTxClosed
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	db := &blockingDB{started: make(chan struct{}), release: make(chan struct{})}
+	m := newVermig(WithDB(db), WithFS(testFiles("1.0.0")), WithAllowDowngrade(true))
+	done := make(chan error)
+	go func() {
+		done <- m.Migrate(t.Context(), "1.0.0")
+	}()
+	<-db.started
+	calls := map[string]func() error{
+		"Migrate": func() error {
+			return m.Migrate(t.Context(), "1.0.0")
+		},
+		"Reset": func() error {
+			return m.Reset(t.Context())
+		},
+		"CIVerify": func() error {
+			return m.CIVerify(t.Context())
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrMigrationInProgress) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrMigrationInProgress)
+		}
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Lint(); err != nil {
+				t.Errorf("lint during migrate failed: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(db.release)
+	if err := <-done; err == nil || errors.Is(err, ErrMigrationInProgress) {
+		t.Fatalf("migrate: got %v, want the query error", err)
+	}
+	if _, err := m.Lint(); err != nil {
+		t.Errorf("lint after migrate failed: %s", err)
+	}
+}
@@ -0,0 +1,54 @@
+package vermig
+
+import (
+	"fmt"
+	"strings"
+)
+
+type LintWarning struct {
+	Scope   string
+	Name    string
+	Path    string
+	Message string
+}
+
+func (m *Vermig) Lint() ([]LintWarning, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return nil, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	var warnings []LintWarning
+	for _, file := range m.files {
+		warn := func(path, message string) {
+			warnings = append(warnings, LintWarning{Scope: file.Scope, Name: file.Name, Path: path, Message: message})
+		}
+		up, down := normalizeSQL(file.Up), normalizeSQL(file.Down)
+		if up != "" && up == down {
+			warn(file.UpPath, "up and down migrations are identical")
+		}
+		if onlyStatements(file.Up, "DROP") {
+			warn(file.UpPath, "up migration only drops objects")
+		}
+		if onlyStatements(file.Down, "CREATE") {
+			warn(file.DownPath, "down migration only creates objects")
+		}
+	}
+	return warnings, nil
+}
+
+func normalizeSQL(query string) string {
+	return strings.Join(strings.Fields(stripComments(query)), " ")
+}
+
+func onlyStatements(query, keyword string) bool {
+	statements := splitStatements(query)
+	if len(statements) == 0 {
+		return false
+	}
+	for _, statement := range statements {
+		if statementKeyword(statement) != keyword {
+			return false
+		}
+	}
+	return true
+}
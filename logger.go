@@ -0,0 +1,62 @@
+package vermig
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"unicode/utf8"
+)
+
+type correlationIDKey struct{}
+
+func (m *Vermig) withCorrelationID(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(correlationIDKey{}).(string); ok {
+		return ctx
+	}
+	id := m.correlationID
+	if id == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+func (m *Vermig) logf(ctx context.Context, format string, args ...any) {
+	logger := m.logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	message := fmt.Sprintf(format, args...)
+	if id := correlationID(ctx); id != "" {
+		message = "[" + id + "] " + message
+	}
+	logger.Println(message)
+}
+
+const defaultEchoSQLLimit = 4096
+
+func (m *Vermig) echoSQL(ctx context.Context, direction Direction, scope, name, query string) {
+	if !m.echoSQLEnabled {
+		return
+	}
+	limit := m.echoSQLLimit
+	if limit <= 0 {
+		limit = defaultEchoSQLLimit
+	}
+	if len(query) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(query[cut]) {
+			cut--
+		}
+		query = fmt.Sprintf("%s\n-- ... truncated %d of %d bytes", query[:cut], len(query)-cut, len(query))
+	}
+	m.logf(ctx, "🔍 %s %s/%s:\n%s", direction, scope, name, query)
+}
@@ -0,0 +1,69 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+)
+
+type Metrics struct {
+	Applied        int
+	Pending        int
+	Orphaned       int
+	Scopes         map[string]ScopeMetrics
+	CurrentVersion string
+	LatestVersion  string
+}
+
+type ScopeMetrics struct {
+	Applied int
+	Pending int
+}
+
+func (m *Vermig) Metrics(ctx context.Context) (Metrics, error) {
+	metrics := Metrics{Scopes: make(map[string]ScopeMetrics)}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return metrics, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return metrics, fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	current, currentVersionErr := highestVersion(
+		migrations, func(Migration) bool {
+			return true
+		},
+	)
+	if currentVersionErr != nil {
+		return metrics, fmt.Errorf("get current version failed: %w", currentVersionErr)
+	}
+	metrics.CurrentVersion = current.String()
+	files := make(map[string]struct{}, len(m.files))
+	for _, file := range m.files {
+		files[file.key()] = struct{}{}
+	}
+	applied := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		applied[migration.key()] = struct{}{}
+		scope := metrics.Scopes[migration.Scope]
+		scope.Applied++
+		metrics.Scopes[migration.Scope] = scope
+		metrics.Applied++
+		if _, exists := files[migration.key()]; !exists {
+			metrics.Orphaned++
+		}
+	}
+	for _, file := range m.files {
+		if _, exists := applied[file.key()]; exists {
+			continue
+		}
+		scope := metrics.Scopes[file.Scope]
+		scope.Pending++
+		metrics.Scopes[file.Scope] = scope
+		metrics.Pending++
+	}
+	if len(m.files) > 0 {
+		metrics.LatestVersion = m.latestVersion().String()
+	}
+	return metrics, nil
+}
@@ -1,6 +1,13 @@
 package vermig
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/semver"
+)
 
 type Migration struct {
 	Id         string    `json:"id"`
@@ -9,10 +16,137 @@ type Migration struct {
 	Major      int64     `db:"major"`
 	Minor      int64     `db:"minor"`
 	Patch      int64     `db:"patch"`
-	Prerelease string    `db:"prelease"`
+	Prerelease string    `db:"prerelease"`
+	Step       int       `db:"step"`
 	Scope      string    `db:"scope"`
 	Up         string    `db:"up"`
 	Down       string    `db:"down"`
 	Checksum   string    `db:"checksum"`
+	SourcePath string    `db:"source_path"`
+	Tags       string    `db:"tags"`
 	CreatedAt  time.Time `db:"created_at"`
 }
+
+var migrationColumns = []string{
+	"id", "name", "version", "major", "minor", "patch", "prerelease", "step", "scope", "up",
+	"down", "checksum", "source_path", "tags", "created_at",
+}
+
+var downgradeColumns = []string{
+	"id", "name", "version", "major", "minor", "patch", "prerelease", "step", "scope", "down", "checksum",
+	"source_path", "tags",
+}
+
+var keyColumns = []string{"id", "name", "version", "step", "scope"}
+
+func sortMigrationsDesc(migrations []Migration) error {
+	versions := make(map[string]*semver.Version, len(migrations))
+	for _, migration := range migrations {
+		version, parseErr := parseVersion(migration.Version)
+		if parseErr != nil {
+			return fmt.Errorf("parse version failed: %w", parseErr)
+		}
+		versions[migration.Version] = version
+	}
+	sort.SliceStable(
+		migrations, func(i, j int) bool {
+			vi, vj := versions[migrations[i].Version], versions[migrations[j].Version]
+			if !vi.Equal(vj) {
+				return vi.GreaterThan(vj)
+			}
+			if migrations[i].Step != migrations[j].Step {
+				return migrations[i].Step > migrations[j].Step
+			}
+			if migrations[i].Scope != migrations[j].Scope {
+				return migrations[i].Scope > migrations[j].Scope
+			}
+			return migrations[i].Name > migrations[j].Name
+		},
+	)
+	return nil
+}
+
+func (m *Vermig) sortDowngrades(migrations []Migration) error {
+	positions := make(map[string]int, len(m.files))
+	for i, file := range m.files {
+		positions[file.key()] = i
+	}
+	var known, orphaned []Migration
+	for _, migration := range migrations {
+		if _, exists := positions[migration.key()]; exists {
+			known = append(known, migration)
+		} else {
+			orphaned = append(orphaned, migration)
+		}
+	}
+	sort.SliceStable(
+		known, func(i, j int) bool {
+			return positions[known[i].key()] > positions[known[j].key()]
+		},
+	)
+	if err := sortMigrationsDesc(orphaned); err != nil {
+		return err
+	}
+	i, j := 0, 0
+	for k := range migrations {
+		if j < len(orphaned) && i < len(known) {
+			below, compareErr := migrationBelow(orphaned[j], known[i])
+			if compareErr != nil {
+				return compareErr
+			}
+			if below {
+				migrations[k] = known[i]
+				i++
+				continue
+			}
+		}
+		if j < len(orphaned) {
+			migrations[k] = orphaned[j]
+			j++
+			continue
+		}
+		migrations[k] = known[i]
+		i++
+	}
+	return nil
+}
+
+func migrationBelow(a, b Migration) (bool, error) {
+	va, parseErr := parseVersion(a.Version)
+	if parseErr != nil {
+		return false, fmt.Errorf("parse version failed: %w", parseErr)
+	}
+	vb, parseErr := parseVersion(b.Version)
+	if parseErr != nil {
+		return false, fmt.Errorf("parse version failed: %w", parseErr)
+	}
+	if !va.Equal(vb) {
+		return va.LessThan(vb), nil
+	}
+	return a.Step < b.Step, nil
+}
+
+func (m Migration) key() string {
+	return migrationKey(m.Scope, m.Version, m.Step)
+}
+
+func migrationKey(scope, version string, step int) string {
+	return scope + "/" + version + "#" + strconv.Itoa(step)
+}
+
+func (m Migration) migrationError(err error) *MigrationError {
+	return &MigrationError{Direction: DirectionDown, Scope: m.Scope, Name: m.Name, Version: m.Version, Err: err}
+}
+
+func (m Migration) file() File {
+	version, _, _ := parseFileVersion(m.Version)
+	return File{
+		Version: version,
+		Step:    m.Step,
+		Scope:   m.Scope,
+		Name:    m.Name,
+		UpPath:  m.SourcePath,
+		Up:      m.Up,
+		Down:    m.Down,
+	}
+}
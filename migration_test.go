@@ -0,0 +1,71 @@
+package vermig
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Masterminds/semver"
+)
+
+func TestSortDowngrades(t *testing.T) {
+	m := newVermig()
+	m.files = []File{
+		{Scope: "zeta", Name: "1.0.0_a", Version: semver.MustParse("1.0.0")},
+		{Scope: "alpha", Name: "1.0.0_b", Version: semver.MustParse("1.0.0")},
+		{Scope: "core", Name: "1.1.0_c", Version: semver.MustParse("1.1.0")},
+	}
+	migrations := []Migration{
+		{Scope: "legacy", Name: "1.0.0_x", Version: "1.0.0"},
+		{Scope: "zeta", Name: "1.0.0_a", Version: "1.0.0"},
+		{Scope: "legacy", Name: "2.0.0_y", Version: "2.0.0"},
+		{Scope: "alpha", Name: "1.0.0_b", Version: "1.0.0"},
+		{Scope: "core", Name: "1.1.0_c", Version: "1.1.0"},
+	}
+	if err := m.sortDowngrades(migrations); err != nil {
+		t.Fatalf("sort downgrades failed: %s", err)
+	}
+	var got []string
+	for _, migration := range migrations {
+		got = append(got, migration.Scope+"/"+migration.Name)
+	}
+	want := []string{"legacy/2.0.0_y", "core/1.1.0_c", "legacy/1.0.0_x", "alpha/1.0.0_b", "zeta/1.0.0_a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDowngradeReversesApplyOrder(t *testing.T) {
+	db := testDB(t)
+	files := fstest.MapFS{
+		"zeta/1.0.0_b_up.sql":    {Data: []byte("CREATE TABLE t_b (id int PRIMARY KEY);")},
+		"zeta/1.0.0_b_down.sql":  {Data: []byte("DROP TABLE t_b;")},
+		"alpha/1.0.0_a_up.sql":   {Data: []byte("-- vermig:requires zeta/1.0.0\nCREATE TABLE t_a (id int REFERENCES t_b);")},
+		"alpha/1.0.0_a_down.sql": {Data: []byte("DROP TABLE t_a;")},
+		"alpha/1.1.0_c_up.sql":   {Data: []byte("CREATE TABLE t_c (id int);")},
+		"alpha/1.1.0_c_down.sql": {Data: []byte("DROP TABLE t_c;")},
+	}
+	var history []string
+	m := testVermig(
+		t, db, files, WithAllowDowngrade(true), WithEventHandler(
+			func(event Event) {
+				if event.Type == EventMigrationApplied || event.Type == EventMigrationReverted {
+					history = append(history, string(event.Direction)+" "+event.Scope+"/"+event.Name)
+				}
+			},
+		),
+	)
+	if err := m.Migrate(t.Context(), "1.1.0"); err != nil {
+		t.Fatalf("migrate failed: %s", err)
+	}
+	if err := m.Reset(t.Context()); err != nil {
+		t.Fatalf("reset failed: %s", err)
+	}
+	want := []string{
+		"up zeta/1.0.0_b_up.sql", "up alpha/1.0.0_a_up.sql", "up alpha/1.1.0_c_up.sql",
+		"down alpha/1.1.0_c_up.sql", "down alpha/1.0.0_a_up.sql", "down zeta/1.0.0_b_up.sql",
+	}
+	if !slices.Equal(history, want) {
+		t.Errorf("got %v, want %v", history, want)
+	}
+}
@@ -1,6 +1,15 @@
 package vermig
 
-import "embed"
+import (
+	"context"
+	"io"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
 
 type Option func(*Vermig)
 
@@ -10,9 +19,15 @@ func WithDB(db DB) Option {
 	}
 }
 
-func WithFS(fs embed.FS) Option {
+func WithFS(fs fs.FS) Option {
+	return func(v *Vermig) {
+		v.sources = append(v.sources, Source{FS: fs})
+	}
+}
+
+func WithSource(source Source) Option {
 	return func(v *Vermig) {
-		v.fs = fs
+		v.sources = append(v.sources, source)
 	}
 }
 
@@ -21,3 +36,249 @@ func WithAllowDowngrade(allowDowngrade bool) Option {
 		v.allowDowngrade = allowDowngrade
 	}
 }
+
+func WithStrictOrphans(strictOrphans bool) Option {
+	return func(v *Vermig) {
+		v.strictOrphans = strictOrphans
+	}
+}
+
+func WithoutAutoCreate(withoutAutoCreate bool) Option {
+	return func(v *Vermig) {
+		v.withoutAutoCreate = withoutAutoCreate
+	}
+}
+
+func WithExtraColumns(extraColumns map[string]func(File) any) Option {
+	return func(v *Vermig) {
+		v.extraColumns = extraColumns
+	}
+}
+
+func WithIdempotentInsert(idempotentInsert bool) Option {
+	return func(v *Vermig) {
+		v.idempotentInsert = idempotentInsert
+	}
+}
+
+func WithTxOptions(txOptions pgx.TxOptions) Option {
+	return func(v *Vermig) {
+		v.txOptions = txOptions
+	}
+}
+
+func WithRootScope(rootScope string) Option {
+	return func(v *Vermig) {
+		v.rootScope = rootScope
+	}
+}
+
+func WithEventHandler(eventHandler func(Event)) Option {
+	return func(v *Vermig) {
+		v.eventHandler = eventHandler
+	}
+}
+
+func WithSplitStatements(splitStatements bool) Option {
+	return func(v *Vermig) {
+		v.statementSplitting = splitStatements
+	}
+}
+
+func WithMaxVersion(maxVersion string) Option {
+	return func(v *Vermig) {
+		v.maxVersion = maxVersion
+	}
+}
+
+func WithSkipPredicate(skipPredicate func(File) bool) Option {
+	return func(v *Vermig) {
+		v.skipPredicate = skipPredicate
+	}
+}
+
+func WithSerialID(serialID bool) Option {
+	return func(v *Vermig) {
+		v.serialID = serialID
+	}
+}
+
+func WithAfterCommit(afterCommit func(ctx context.Context, applied, reverted []Migration) error) Option {
+	return func(v *Vermig) {
+		v.afterCommit = afterCommit
+	}
+}
+
+func WithLogger(logger *log.Logger) Option {
+	return func(v *Vermig) {
+		v.logger = logger
+	}
+}
+
+func WithCorrelationID(correlationID string) Option {
+	return func(v *Vermig) {
+		v.correlationID = correlationID
+	}
+}
+
+func WithVersionParser(versionParser func(filename string) (string, error)) Option {
+	return func(v *Vermig) {
+		v.versionParser = versionParser
+	}
+}
+
+func WithChecksumNormalization(checksumNormalization ChecksumNormalization) Option {
+	return func(v *Vermig) {
+		v.checksumNormalization = checksumNormalization
+	}
+}
+
+func WithBlockDestructiveDown(blockDestructiveDown bool) Option {
+	return func(v *Vermig) {
+		v.blockDestructiveDown = blockDestructiveDown
+	}
+}
+
+func WithBatchSize(batchSize int) Option {
+	return func(v *Vermig) {
+		v.batchSize = batchSize
+	}
+}
+
+func WithNameColumnLength(nameColumnLength int) Option {
+	return func(v *Vermig) {
+		v.nameColumnLength = nameColumnLength
+	}
+}
+
+func WithManagedSchema(managedSchema string) Option {
+	return func(v *Vermig) {
+		v.managedSchema = managedSchema
+	}
+}
+
+func WithDevMode(devMode bool) Option {
+	return func(v *Vermig) {
+		v.devMode = devMode
+	}
+}
+
+func WithStatementBuilder(statementBuilder squirrel.StatementBuilderType) Option {
+	return func(v *Vermig) {
+		v.statementBuilder = statementBuilder
+	}
+}
+
+func WithReportWriter(reportWriter io.Writer) Option {
+	return func(v *Vermig) {
+		v.reportWriter = reportWriter
+	}
+}
+
+func WithApplicationName(applicationName string) Option {
+	return func(v *Vermig) {
+		v.applicationName = applicationName
+	}
+}
+
+func WithDeferredRecording(deferredRecording bool) Option {
+	return func(v *Vermig) {
+		v.deferredRecording = deferredRecording
+	}
+}
+
+func WithPreamble(preamble string) Option {
+	return func(v *Vermig) {
+		v.preamble = preamble
+	}
+}
+
+func WithSeed(seed func(ctx context.Context, tx pgx.Tx) error) Option {
+	return func(v *Vermig) {
+		v.seeds = append(v.seeds, seed)
+	}
+}
+
+func WithDownCheck(downCheck DownCheck) Option {
+	return func(v *Vermig) {
+		v.downCheck = downCheck
+	}
+}
+
+func WithSQLTransform(sqlTransform func(file File, sql string) (string, error)) Option {
+	return func(v *Vermig) {
+		v.sqlTransform = sqlTransform
+	}
+}
+
+func WithVersionTable(versionTable bool) Option {
+	return func(v *Vermig) {
+		v.versionTable = versionTable
+	}
+}
+
+func WithStoreDownSQL(storeDownSQL bool) Option {
+	return func(v *Vermig) {
+		v.withoutDownSQL = !storeDownSQL
+	}
+}
+
+func WithLayout(layout Layout) Option {
+	return func(v *Vermig) {
+		v.layout = layout
+	}
+}
+
+func WithStopSignal(stopSignal <-chan struct{}) Option {
+	return func(v *Vermig) {
+		v.stopSignal = stopSignal
+	}
+}
+
+func WithColumnMapping(columnMapping map[string]string) Option {
+	return func(v *Vermig) {
+		v.columnMapping = columnMapping
+	}
+}
+
+func WithEchoSQL(echoSQL bool) Option {
+	return func(v *Vermig) {
+		v.echoSQLEnabled = echoSQL
+	}
+}
+
+func WithEchoSQLLimit(echoSQLLimit int) Option {
+	return func(v *Vermig) {
+		v.echoSQLLimit = echoSQLLimit
+	}
+}
+
+func WithEnsureTable(ensureTable bool) Option {
+	return func(v *Vermig) {
+		v.ensureTableOnMigrate = ensureTable
+	}
+}
+
+func WithOverlay(overlay Source) Option {
+	return func(v *Vermig) {
+		v.overlays = append(v.overlays, overlay)
+	}
+}
+
+func WithPlanApprover(planApprover func(plan []PlannedOp) error) Option {
+	return func(v *Vermig) {
+		v.planApprover = planApprover
+	}
+}
+
+func WithAllowedWindow(allowedWindow func(now time.Time) bool) Option {
+	return func(v *Vermig) {
+		v.allowedWindow = allowedWindow
+	}
+}
+
+func WithOrdering(ordering OrderingMode) Option {
+	return func(v *Vermig) {
+		v.ordering = ordering
+	}
+}
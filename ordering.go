@@ -0,0 +1,44 @@
+package vermig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+type OrderingMode int
+
+const (
+	OrderingSemVer OrderingMode = iota
+	OrderingFilename
+)
+
+const filenameVersionChunk = 9
+
+func filenameVersion(raw string) (*semver.Version, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > 3*filenameVersionChunk || strings.Trim(raw, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid filename version %q: expected up to %d digits", raw, 3*filenameVersionChunk)
+	}
+	var parts [3]int64
+	for i := 2; i >= 0 && raw != ""; i-- {
+		start := max(0, len(raw)-filenameVersionChunk)
+		part, parseErr := strconv.ParseInt(raw[start:], 10, 64)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid filename version %q: %w", raw, parseErr)
+		}
+		parts[i] = part
+		raw = raw[:start]
+	}
+	return semver.NewVersion(fmt.Sprintf("%d.%d.%d", parts[0], parts[1], parts[2]))
+}
+
+func (m *Vermig) parseOrderedVersion(raw string) (*semver.Version, int, error) {
+	if m.ordering == OrderingFilename {
+		pv, parseErr := filenameVersion(raw)
+		return pv, 0, parseErr
+	}
+	return parseFileVersion(raw)
+}
@@ -0,0 +1,38 @@
+package vermig
+
+import "fmt"
+
+func (m *Vermig) collectOverlays() error {
+	files, repeatables := m.files, m.repeatables
+	m.files, m.repeatables = nil, nil
+	for _, source := range m.overlays {
+		if err := m.collectSource(source); err != nil {
+			return fmt.Errorf("scan overlay migrations failed: %w", err)
+		}
+	}
+	if err := checkVersionCollisions(m.files); err != nil {
+		return err
+	}
+	m.files = mergeOverlay(files, m.files, File.key)
+	m.repeatables = mergeOverlay(
+		repeatables, m.repeatables, func(file File) string {
+			return file.Scope + "/" + file.Name
+		},
+	)
+	return nil
+}
+
+func mergeOverlay(base, overlay []File, key func(File) string) []File {
+	index := make(map[string]int, len(base))
+	for i, file := range base {
+		index[key(file)] = i
+	}
+	for _, file := range overlay {
+		if i, exists := index[key(file)]; exists {
+			base[i] = file
+			continue
+		}
+		base = append(base, file)
+	}
+	return base
+}
@@ -0,0 +1,106 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+)
+
+type plan struct {
+	down             []Migration
+	up               []File
+	skipped          []File
+	downgradeBlocked bool
+}
+
+func (m *Vermig) buildPlan(
+	ctx context.Context, db DB, targetVersion *semver.Version, include func(File) bool,
+) (plan, error) {
+	var p plan
+	columns := keyColumns
+	if m.allowDowngrade {
+		columns = downgradeColumns
+	}
+	higherMigrations, findMigrationsErr := m.findHigherVersionMigrations(ctx, db, targetVersion, columns)
+	if findMigrationsErr != nil {
+		return p, fmt.Errorf("find higher version migrations failed: %w", findMigrationsErr)
+	}
+	applied, findAppliedErr := m.findAppliedKeys(ctx, db)
+	if findAppliedErr != nil {
+		return p, fmt.Errorf("find applied migrations failed: %w", findAppliedErr)
+	}
+	return m.planFiles(higherMigrations, applied, targetVersion, include)
+}
+
+func (m *Vermig) planFiles(
+	higherMigrations []Migration, applied map[string]struct{}, targetVersion *semver.Version, include func(File) bool,
+) (plan, error) {
+	var p plan
+	if len(higherMigrations) > 0 {
+		if m.allowDowngrade {
+			p.down = higherMigrations
+		} else {
+			p.downgradeBlocked = true
+		}
+	}
+	planned := make(map[string]struct{})
+	for _, file := range m.files {
+		if file.Version.GreaterThan(targetVersion) || (include != nil && !include(file)) {
+			continue
+		}
+		if _, exists := applied[file.key()]; exists {
+			continue
+		}
+		if m.skipPredicate != nil && m.skipPredicate(file) {
+			p.skipped = append(p.skipped, file)
+			continue
+		}
+		if err := checkDependencies(applied, planned, file); err != nil {
+			return p, err
+		}
+		planned[file.key()] = struct{}{}
+		p.up = append(p.up, file)
+	}
+	return p, nil
+}
+
+func (m *Vermig) limitBatch(p *plan) bool {
+	if m.batchSize <= 0 || len(p.down)+len(p.up) <= m.batchSize {
+		return false
+	}
+	if len(p.down) >= m.batchSize {
+		p.down = p.down[:m.batchSize]
+		p.up = nil
+		return true
+	}
+	p.up = p.up[:m.batchSize-len(p.down)]
+	return true
+}
+
+func (m *Vermig) upToDate(ctx context.Context, targetVersion *semver.Version, include func(File) bool) (plan, bool, error) {
+	if err := m.verifyIntegrity(ctx, m.db); err != nil {
+		return plan{}, false, fmt.Errorf("verify integrity failed: %w", err)
+	}
+	p, buildPlanErr := m.buildPlan(ctx, m.db, targetVersion, include)
+	if buildPlanErr != nil {
+		return p, false, fmt.Errorf("build plan failed: %w", buildPlanErr)
+	}
+	if len(p.up) > 0 || len(p.down) > 0 || (m.freshTable.Load() && len(m.seeds) > 0) {
+		return p, false, nil
+	}
+	repeatablesPending, repeatablesPendingErr := m.repeatablesPending(ctx, m.db)
+	if repeatablesPendingErr != nil {
+		return p, false, fmt.Errorf("check repeatable migrations failed: %w", repeatablesPendingErr)
+	}
+	if repeatablesPending {
+		return p, false, nil
+	}
+	if p.downgradeBlocked {
+		m.logf(ctx, "⚠️ downgrade not enabled")
+	}
+	for _, file := range p.skipped {
+		m.logf(ctx, "⏭️ %s/%s: skipped", file.Scope, file.Name)
+	}
+	return p, true, nil
+}
@@ -0,0 +1,191 @@
+package vermig
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Masterminds/semver"
+)
+
+var planTests = []struct {
+	name           string
+	applied        []string
+	files          []string
+	target         string
+	allowDowngrade bool
+	wantUp         []string
+	wantDown       []string
+	wantBlocked    bool
+}{
+	{
+		name:   "file at the target version is applied",
+		files:  []string{"1.0.0", "1.1.0"},
+		target: "1.1.0",
+		wantUp: []string{"1.0.0", "1.1.0"},
+	},
+	{
+		name:    "gap below an applied version is filled",
+		applied: []string{"1.0.0", "2.0.0"},
+		files:   []string{"1.0.0", "1.1.0", "2.0.0"},
+		target:  "2.0.0",
+		wantUp:  []string{"1.1.0"},
+	},
+	{
+		name:        "blocked downgrade still plans pending ups",
+		applied:     []string{"1.0.0", "2.0.0"},
+		files:       []string{"1.0.0", "1.1.0", "2.0.0"},
+		target:      "1.5.0",
+		wantUp:      []string{"1.1.0"},
+		wantBlocked: true,
+	},
+	{
+		name:           "target below applied reverts highest first",
+		applied:        []string{"1.0.0", "1.1.0", "2.0.0"},
+		files:          []string{"1.0.0", "1.1.0", "2.0.0"},
+		target:         "1.0.0",
+		allowDowngrade: true,
+		wantDown:       []string{"2.0.0", "1.1.0"},
+	},
+	{
+		name:   "files above the target are not applied",
+		files:  []string{"1.0.0", "1.1.0"},
+		target: "1.0.0",
+		wantUp: []string{"1.0.0"},
+	},
+	{
+		name:    "applied target plans nothing",
+		applied: []string{"1.0.0", "1.1.0"},
+		files:   []string{"1.0.0", "1.1.0"},
+		target:  "1.1.0",
+	},
+	{
+		name:   "prerelease is below its release",
+		files:  []string{"1.0.0-rc.1", "1.0.0"},
+		target: "1.0.0",
+		wantUp: []string{"1.0.0-rc.1", "1.0.0"},
+	},
+	{
+		name:           "release is above its prerelease target",
+		applied:        []string{"1.0.0-rc.1", "1.0.0"},
+		files:          []string{"1.0.0-rc.1", "1.0.0"},
+		target:         "1.0.0-rc.1",
+		allowDowngrade: true,
+		wantDown:       []string{"1.0.0"},
+	},
+}
+
+func TestPlanFiles(t *testing.T) {
+	for _, test := range planTests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				m := newVermig(WithAllowDowngrade(test.allowDowngrade))
+				m.files = unitFiles(test.files...)
+				target := semver.MustParse(test.target)
+				rows := make([]Migration, len(test.applied))
+				applied := make(map[string]struct{}, len(test.applied))
+				for i, version := range test.applied {
+					rows[i] = Migration{Scope: "app", Name: version + "_create", Version: version}
+					applied[rows[i].key()] = struct{}{}
+				}
+				higher, filterErr := filterHigherVersions(rows, target)
+				if filterErr != nil {
+					t.Fatalf("filter higher versions failed: %s", filterErr)
+				}
+				if err := m.sortDowngrades(higher); err != nil {
+					t.Fatalf("sort downgrades failed: %s", err)
+				}
+				p, planFilesErr := m.planFiles(higher, applied, target, nil)
+				if planFilesErr != nil {
+					t.Fatalf("plan files failed: %s", planFilesErr)
+				}
+				checkPlan(t, p, test.wantUp, test.wantDown, test.wantBlocked)
+			},
+		)
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	for _, test := range planTests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				db := testDB(t)
+				if len(test.applied) > 0 {
+					setup := testVermig(t, db, testFiles(test.applied...))
+					if err := setup.Migrate(t.Context(), test.applied[len(test.applied)-1]); err != nil {
+						t.Fatalf("apply %v failed: %s", test.applied, err)
+					}
+				}
+				m := testVermig(t, db, testFiles(test.files...), WithAllowDowngrade(test.allowDowngrade))
+				m, collectFilesErr := m.collectFiles()
+				if collectFilesErr != nil {
+					t.Fatalf("collect migrations failed: %s", collectFilesErr)
+				}
+				target, parseErr := parseVersion(test.target)
+				if parseErr != nil {
+					t.Fatalf("parse version failed: %s", parseErr)
+				}
+				p, buildPlanErr := m.buildPlan(t.Context(), db, target, nil)
+				if buildPlanErr != nil {
+					t.Fatalf("build plan failed: %s", buildPlanErr)
+				}
+				checkPlan(t, p, test.wantUp, test.wantDown, test.wantBlocked)
+			},
+		)
+	}
+}
+
+func checkPlan(t *testing.T, p plan, wantUp, wantDown []string, wantBlocked bool) {
+	t.Helper()
+	var up, down []string
+	for _, file := range p.up {
+		up = append(up, file.Version.String())
+	}
+	for _, migration := range p.down {
+		down = append(down, migration.Version)
+	}
+	if !slices.Equal(up, wantUp) {
+		t.Errorf("up: got %v, want %v", up, wantUp)
+	}
+	if !slices.Equal(down, wantDown) {
+		t.Errorf("down: got %v, want %v", down, wantDown)
+	}
+	if p.downgradeBlocked != wantBlocked {
+		t.Errorf("downgrade blocked: got %t, want %t", p.downgradeBlocked, wantBlocked)
+	}
+}
+
+func TestCheckApproved(t *testing.T) {
+	m := newVermig(
+		WithPlanApprover(
+			func([]PlannedOp) error {
+				return nil
+			},
+		),
+	)
+	files := unitFiles("1.0.0", "1.1.0")
+	approved, approvePlanErr := m.approvePlan(plan{up: files})
+	if approvePlanErr != nil {
+		t.Fatalf("approve plan failed: %s", approvePlanErr)
+	}
+	if err := m.checkApproved(plan{up: files}, approved); err != nil {
+		t.Errorf("same plan: %s", err)
+	}
+	if err := m.checkApproved(plan{up: files[1:]}, approved[1:]); err != nil {
+		t.Errorf("remaining batch: %s", err)
+	}
+	if err := m.checkApproved(plan{up: files[1:]}, approved); !errors.Is(err, ErrPlanChanged) {
+		t.Errorf("fewer ops: got %v, want %v", err, ErrPlanChanged)
+	}
+	if err := m.checkApproved(plan{up: unitFiles("1.0.0", "1.0.5", "1.1.0")}, approved); !errors.Is(err, ErrPlanChanged) {
+		t.Errorf("new op: got %v, want %v", err, ErrPlanChanged)
+	}
+}
+
+func unitFiles(versions ...string) []File {
+	files := make([]File, len(versions))
+	for i, version := range versions {
+		files[i] = File{Version: semver.MustParse(version), Scope: "app", Name: version + "_create"}
+	}
+	return files
+}
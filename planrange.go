@@ -0,0 +1,119 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+type PlannedOp struct {
+	Direction Direction
+	Scope     string
+	Name      string
+	Version   string
+	File      File
+}
+
+func (p plan) ops() []PlannedOp {
+	ops := make([]PlannedOp, 0, len(p.down)+len(p.up))
+	for _, migration := range p.down {
+		ops = append(
+			ops, PlannedOp{
+				Direction: DirectionDown,
+				Scope:     migration.Scope,
+				Name:      migration.Name,
+				Version:   migration.Version,
+				File:      migration.file(),
+			},
+		)
+	}
+	for _, file := range p.up {
+		ops = append(
+			ops, PlannedOp{
+				Direction: DirectionUp,
+				Scope:     file.Scope,
+				Name:      file.Name,
+				Version:   file.Version.String(),
+				File:      file,
+			},
+		)
+	}
+	return ops
+}
+
+func (m *Vermig) approvePlan(p plan) ([]PlannedOp, error) {
+	if m.planApprover == nil {
+		return nil, nil
+	}
+	ops := p.ops()
+	if len(ops) == 0 {
+		return ops, nil
+	}
+	if err := m.planApprover(ops); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPlanRejected, err)
+	}
+	return ops, nil
+}
+
+func (m *Vermig) checkApproved(p plan, approved []PlannedOp) error {
+	if m.planApprover == nil {
+		return nil
+	}
+	ops := p.ops()
+	for i := 0; i < max(len(ops), len(approved)); i++ {
+		switch {
+		case i >= len(approved):
+			return fmt.Errorf("%w: %s %s/%s was not approved", ErrPlanChanged, ops[i].Direction, ops[i].Scope, ops[i].Name)
+		case i >= len(ops):
+			return fmt.Errorf("%w: %s %s/%s is no longer planned", ErrPlanChanged, approved[i].Direction, approved[i].Scope, approved[i].Name)
+		case ops[i].Direction != approved[i].Direction || ops[i].Scope != approved[i].Scope ||
+			ops[i].Name != approved[i].Name || ops[i].Version != approved[i].Version:
+			return fmt.Errorf(
+				"%w: approved %s %s/%s, planned %s %s/%s", ErrPlanChanged,
+				approved[i].Direction, approved[i].Scope, approved[i].Name, ops[i].Direction, ops[i].Scope, ops[i].Name,
+			)
+		}
+	}
+	return nil
+}
+
+func (m *Vermig) PlanRange(ctx context.Context, from, to string) ([]PlannedOp, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return nil, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	fromVersion, resolveFromErr := m.resolveTargetVersion(from)
+	if resolveFromErr != nil {
+		return nil, fmt.Errorf("parse from version failed: %w", resolveFromErr)
+	}
+	toVersion, resolveToErr := m.resolveTargetVersion(to)
+	if resolveToErr != nil {
+		return nil, fmt.Errorf("parse to version failed: %w", resolveToErr)
+	}
+	direction, lower, upper := DirectionUp, fromVersion, toVersion
+	if toVersion.LessThan(fromVersion) {
+		direction, lower, upper = DirectionDown, toVersion, fromVersion
+	}
+	var files []File
+	for _, file := range m.files {
+		if file.Version.GreaterThan(lower) && !file.Version.GreaterThan(upper) {
+			files = append(files, file)
+		}
+	}
+	if direction == DirectionDown {
+		slices.Reverse(files)
+	}
+	ops := make([]PlannedOp, 0, len(files))
+	for _, file := range files {
+		ops = append(
+			ops, PlannedOp{
+				Direction: direction,
+				Scope:     file.Scope,
+				Name:      file.Name,
+				Version:   file.Version.String(),
+				File:      file,
+			},
+		)
+	}
+	return ops, nil
+}
@@ -0,0 +1,30 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const postDirective = "post"
+
+func (m *Vermig) runPostSteps(ctx context.Context, files []File) error {
+	var errs []error
+	for _, file := range files {
+		for _, statement := range file.Directives[postDirective] {
+			if statement == "" {
+				continue
+			}
+			if _, err := m.db.Exec(ctx, statement); err != nil {
+				m.logf(ctx, "⚠️ %s/%s: post step %q failed: %v", file.Scope, file.Name, statement, err)
+				errs = append(errs, fmt.Errorf("%s/%s: %q: %w", file.Scope, file.Name, statement, err))
+				continue
+			}
+			m.logf(ctx, "🧹 %s/%s: post step %q: ✅", file.Scope, file.Name, statement)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrPostStepFailed, errors.Join(errs...))
+	}
+	return nil
+}
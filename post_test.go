@@ -0,0 +1,43 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPostStepsAfterFailedHook(t *testing.T) {
+	db := testDB(t)
+	hookErr := errors.New("cache unavailable")
+	files := fstest.MapFS{
+		"1.0.0_create-a_up.sql": &fstest.MapFile{
+			Data: []byte("-- vermig:post=CREATE TABLE post_a (id int)\nCREATE TABLE a (id int);"),
+		},
+		"1.0.0_create-a_down.sql": &fstest.MapFile{Data: []byte("DROP TABLE a;")},
+		"1.1.0_create-b_up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE b (id int);")},
+		"1.1.0_create-b_down.sql": &fstest.MapFile{Data: []byte("DROP TABLE b;")},
+	}
+	m := testVermig(
+		t, db, files, WithBatchSize(1), WithAfterCommit(
+			func(context.Context, []Migration, []Migration) error {
+				return hookErr
+			},
+		),
+	)
+	if err := m.Migrate(t.Context(), "1.1.0"); !errors.Is(err, hookErr) {
+		t.Fatalf("migrate: got %v, want %v", err, hookErr)
+	}
+	var postRan, laterApplied bool
+	if err := db.QueryRow(
+		t.Context(), "SELECT to_regclass('post_a') IS NOT NULL, to_regclass('b') IS NOT NULL",
+	).Scan(&postRan, &laterApplied); err != nil {
+		t.Fatalf("check tables failed: %s", err)
+	}
+	if !postRan {
+		t.Error("post step did not run after the hook failed")
+	}
+	if laterApplied {
+		t.Error("batch after the failed hook was applied")
+	}
+}
@@ -0,0 +1,18 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) runPreamble(ctx context.Context, tx pgx.Tx) error {
+	if m.preamble == "" {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, m.preamble); err != nil {
+		return fmt.Errorf("exec preamble failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+type MigrationOrder int
+
+const (
+	OrderByVersion MigrationOrder = iota
+	OrderByAppliedAt
+	OrderByName
+	OrderByScope
+)
+
+type MigrationFilter struct {
+	Scope        string
+	MinVersion   string
+	MaxVersion   string
+	AppliedAfter time.Time
+	NameContains string
+	OrderBy      MigrationOrder
+	Descending   bool
+	Limit        uint64
+	Offset       uint64
+}
+
+func (m *Vermig) Query(ctx context.Context, filter MigrationFilter) ([]Migration, error) {
+	builder := m.statementBuilder.Select().
+		Columns(m.selectCols(migrationColumns...)...).
+		From(m.table()).
+		Where(m.col("version") + " IS NOT NULL")
+	core := "(" + m.col("major") + ", " + m.col("minor") + ", " + m.col("patch") + ")"
+	if filter.Scope != "" {
+		builder = builder.Where(squirrel.Eq{m.col("scope"): filter.Scope})
+	}
+	if filter.MinVersion != "" {
+		pv, parseVersionErr := parseVersion(filter.MinVersion)
+		if parseVersionErr != nil {
+			return nil, fmt.Errorf("parse min version failed: %w", parseVersionErr)
+		}
+		builder = builder.Where(core+" >= (?, ?, ?)", pv.Major(), pv.Minor(), pv.Patch())
+	}
+	if filter.MaxVersion != "" {
+		pv, parseVersionErr := parseVersion(filter.MaxVersion)
+		if parseVersionErr != nil {
+			return nil, fmt.Errorf("parse max version failed: %w", parseVersionErr)
+		}
+		builder = builder.Where(core+" <= (?, ?, ?)", pv.Major(), pv.Minor(), pv.Patch())
+	}
+	if !filter.AppliedAfter.IsZero() {
+		builder = builder.Where(squirrel.Gt{m.col("created_at"): filter.AppliedAfter})
+	}
+	if filter.NameContains != "" {
+		builder = builder.Where("strpos("+m.col("name")+", ?) > 0", filter.NameContains)
+	}
+	direction := " ASC"
+	if filter.Descending {
+		direction = " DESC"
+	}
+	switch filter.OrderBy {
+	case OrderByAppliedAt:
+		builder = builder.OrderBy(m.col("created_at") + direction)
+	case OrderByName:
+		builder = builder.OrderBy(m.col("name") + direction)
+	case OrderByScope:
+		builder = builder.OrderBy(m.col("scope") + direction)
+	}
+	builder = builder.OrderBy(
+		m.col("major")+direction, m.col("minor")+direction, m.col("patch")+direction,
+		"("+m.col("prerelease")+" = '')"+direction, m.col("prerelease")+direction, m.col("step")+direction,
+		m.col("scope")+direction, m.col("name")+direction,
+	)
+	if filter.Limit > 0 {
+		builder = builder.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		builder = builder.Offset(filter.Offset)
+	}
+	sql, args, createSqlErr := builder.ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create query migrations sql failed: %w", createSqlErr)
+	}
+	var result []Migration
+	if err := pgxscan.Select(ctx, m.db, &result, sql, args...); err != nil {
+		return nil, fmt.Errorf("query migrations failed: %w", m.diagnoseTableErr(ctx, m.db, err))
+	}
+	return result, nil
+}
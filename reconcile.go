@@ -0,0 +1,172 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type ReconcileIssueKind string
+
+const (
+	ReconcileMissingTable     ReconcileIssueKind = "missing_table"
+	ReconcileUnrecordedTable  ReconcileIssueKind = "unrecorded_table"
+	ReconcileChecksumMismatch ReconcileIssueKind = "checksum_mismatch"
+)
+
+type ReconcileIssue struct {
+	Kind    ReconcileIssueKind
+	Scope   string
+	Name    string
+	Version string
+	Table   string
+}
+
+type ReconcileReport struct {
+	Issues []ReconcileIssue
+}
+
+func (r ReconcileReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+var (
+	tableNamePattern = `(?:"[^"]+"|[\w$]+)(?:\.(?:"[^"]+"|[\w$]+))?`
+	createTableRegex = regexp.MustCompile(
+		`(?is)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(` +
+			tableNamePattern + `)`,
+	)
+	dropTableRegex = regexp.MustCompile(
+		`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(` + tableNamePattern + `(?:\s*,\s*` + tableNamePattern + `)*)`,
+	)
+)
+
+func (m *Vermig) Reconcile(ctx context.Context) (ReconcileReport, error) {
+	var report ReconcileReport
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return report, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return report, fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	if err := sortMigrationsDesc(migrations); err != nil {
+		return report, fmt.Errorf("sort migrations failed: %w", err)
+	}
+	files := make(map[string]File, len(m.files))
+	for _, file := range m.files {
+		files[file.key()] = file
+	}
+	applied := make(map[string]struct{}, len(migrations))
+	tables := make(map[string]Migration)
+	var tableOrder []string
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		applied[migration.key()] = struct{}{}
+		if file, exists := files[migration.key()]; exists && !checksumMatches(file, migration.Checksum) {
+			report.Issues = append(
+				report.Issues, ReconcileIssue{
+					Kind:    ReconcileChecksumMismatch,
+					Scope:   migration.Scope,
+					Name:    migration.Name,
+					Version: migration.Version,
+				},
+			)
+		}
+		created, dropped := tableChanges(migration.Up)
+		for _, table := range dropped {
+			delete(tables, normalizeTableName(table))
+		}
+		for _, table := range created {
+			if _, exists := tables[normalizeTableName(table)]; !exists {
+				tableOrder = append(tableOrder, table)
+			}
+			tables[normalizeTableName(table)] = migration
+		}
+	}
+	for _, table := range tableOrder {
+		migration, expected := tables[normalizeTableName(table)]
+		if !expected {
+			continue
+		}
+		exists, existsErr := m.tableExists(ctx, table)
+		if existsErr != nil {
+			return report, fmt.Errorf("check table existence failed: %w", existsErr)
+		}
+		if !exists {
+			report.Issues = append(
+				report.Issues, ReconcileIssue{
+					Kind:    ReconcileMissingTable,
+					Scope:   migration.Scope,
+					Name:    migration.Name,
+					Version: migration.Version,
+					Table:   table,
+				},
+			)
+		}
+	}
+	for _, file := range m.files {
+		if _, exists := applied[file.key()]; exists {
+			continue
+		}
+		created, _ := tableChanges(file.Up)
+		for _, table := range created {
+			exists, existsErr := m.tableExists(ctx, table)
+			if existsErr != nil {
+				return report, fmt.Errorf("check table existence failed: %w", existsErr)
+			}
+			if exists {
+				report.Issues = append(
+					report.Issues, ReconcileIssue{
+						Kind:    ReconcileUnrecordedTable,
+						Scope:   file.Scope,
+						Name:    file.Name,
+						Version: file.Version.String(),
+						Table:   table,
+					},
+				)
+			}
+		}
+	}
+	return report, nil
+}
+
+func (m *Vermig) tableExists(ctx context.Context, table string) (bool, error) {
+	var exists bool
+	if err := m.db.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func tableChanges(query string) (created, dropped []string) {
+	for _, statement := range splitStatements(query) {
+		statement = strings.TrimSpace(stripComments(statement))
+		if match := createTableRegex.FindStringSubmatch(statement); match != nil {
+			created = append(created, match[1])
+			continue
+		}
+		if match := dropTableRegex.FindStringSubmatch(statement); match != nil {
+			for _, table := range strings.Split(match[1], ",") {
+				if table = strings.TrimSpace(table); table != "" {
+					dropped = append(dropped, table)
+				}
+			}
+		}
+	}
+	return created, dropped
+}
+
+func normalizeTableName(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		if strings.HasPrefix(part, `"`) {
+			parts[i] = strings.Trim(part, `"`)
+			continue
+		}
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, ".")
+}
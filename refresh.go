@@ -0,0 +1,66 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) Refresh(ctx context.Context, scope, version string) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	if !m.devMode {
+		return ErrDevModeRequired
+	}
+	pv, step, parseVersionErr := m.parseOrderedVersion(version)
+	if parseVersionErr != nil {
+		return fmt.Errorf("parse version failed: %w", parseVersionErr)
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	key := migrationKey(scope, pv.String(), step)
+	var file *File
+	for i := range m.files {
+		if m.files[i].key() == key {
+			file = &m.files[i]
+			break
+		}
+	}
+	if file == nil {
+		return fmt.Errorf("%w: no file for %s/%s", ErrMigrationNotFound, scope, pv)
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			if err := m.lockTable(ctx, tx); err != nil {
+				return fmt.Errorf("lock migrations table failed: %w", err)
+			}
+			migration, getMigrationErr := m.getMigration(ctx, tx, scope, pv, step)
+			if getMigrationErr != nil {
+				return fmt.Errorf("get migration failed: %w", getMigrationErr)
+			}
+			if err := m.migrateDown(ctx, tx, []Migration{*migration}); err != nil {
+				return fmt.Errorf("downgrade db failed: %w", err)
+			}
+			if _, err := m.migrateUp(ctx, tx, []File{*file}); err != nil {
+				return fmt.Errorf("upgrade db failed: %w", err)
+			}
+			if err := m.syncVersionTable(ctx, tx); err != nil {
+				return fmt.Errorf("sync version table failed: %w", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return fmt.Errorf("refresh failed: %w", err)
+	}
+	m.logf(ctx, "migrator status: ✅")
+	return nil
+}
@@ -0,0 +1,140 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) RenameScope(ctx context.Context, from, to string) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			if err := m.lockTable(ctx, tx); err != nil {
+				return fmt.Errorf("lock migrations table failed: %w", err)
+			}
+			migrations, findErr := m.findScopeRows(ctx, tx, from)
+			if findErr != nil {
+				return fmt.Errorf("find %s migrations failed: %w", from, findErr)
+			}
+			existing, findExistingErr := m.findScopeRows(ctx, tx, to)
+			if findExistingErr != nil {
+				return fmt.Errorf("find %s migrations failed: %w", to, findExistingErr)
+			}
+			if len(existing) > 0 {
+				return fmt.Errorf("%w: %s", ErrScopeExists, to)
+			}
+			for _, migration := range migrations {
+				if err := m.rescope(ctx, tx, migration, to); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	); err != nil {
+		return fmt.Errorf("rename scope failed: %w", err)
+	}
+	m.logf(ctx, "migrator status: scope %q renamed to %q ✅", from, to)
+	return nil
+}
+
+func (m *Vermig) rescope(ctx context.Context, db DB, migration Migration, scope string) error {
+	sql, args, createSqlErr := m.statementBuilder.Update(m.table()).
+		Set(m.col("scope"), scope).
+		Set(m.col("checksum"), m.rescopeChecksum(migration, scope)).
+		Where(m.col("id")+" = ?::"+m.idType, migration.Id).
+		ToSql()
+	if createSqlErr != nil {
+		return fmt.Errorf("create rename scope sql failed: %w", createSqlErr)
+	}
+	if _, err := db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("rename %s scope failed: %w", migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Vermig) adoptLegacyRootScopes(ctx context.Context, db DB) error {
+	c := m.col
+	sql, args, createSqlErr := m.statementBuilder.Select(m.selectCols("id", "name", "step", "scope", "up", "down", "checksum")...).
+		Column("COALESCE("+c("version")+", '') AS version").
+		From(m.table()+" AS legacy").
+		Where("legacy."+c("scope")+" = legacy."+c("name")).
+		Where("legacy."+c("version")+" IS NOT NULL").
+		Where(
+			"NOT EXISTS (SELECT 1 FROM "+m.table()+" AS root WHERE root."+c("scope")+" = ? AND root."+c("version")+
+				" = legacy."+c("version")+" AND root."+c("step")+" = legacy."+c("step")+")", m.rootScope,
+		).
+		ToSql()
+	if createSqlErr != nil {
+		return fmt.Errorf("create legacy root scope rows sql failed: %w", createSqlErr)
+	}
+	var migrations []Migration
+	if err := pgxscan.Select(ctx, db, &migrations, sql, args...); err != nil {
+		return fmt.Errorf("select legacy root scope rows failed: %w", err)
+	}
+	adopted := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		key := migrationKey(m.rootScope, migration.Version, migration.Step)
+		if _, exists := adopted[key]; exists {
+			continue
+		}
+		if err := m.rescope(ctx, db, migration, m.rootScope); err != nil {
+			return err
+		}
+		adopted[key] = struct{}{}
+	}
+	return nil
+}
+
+func (m *Vermig) findScopeRows(ctx context.Context, db DB, scope string) ([]Migration, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select(m.selectCols("id", "name", "step", "scope", "up", "down", "checksum")...).
+		Column("COALESCE(" + m.col("version") + ", '') AS version").
+		From(m.table()).
+		Where(squirrel.Eq{m.col("scope"): scope}).
+		ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create scope rows sql failed: %w", createSqlErr)
+	}
+	var result []Migration
+	if err := pgxscan.Select(ctx, db, &result, sql, args...); err != nil {
+		return nil, fmt.Errorf("select scope rows failed: %w", err)
+	}
+	return result, nil
+}
+
+func (m *Vermig) rescopeChecksum(migration Migration, scope string) string {
+	for normalization, prefix := range checksumPrefixes {
+		if !strings.HasPrefix(migration.Checksum, prefix) {
+			continue
+		}
+		if migration.Version == "" {
+			file := File{Scope: migration.Scope, Name: migration.Name, Up: migration.Up}
+			if repeatableChecksum(file, normalization) != migration.Checksum {
+				return migration.Checksum
+			}
+			file.Scope = scope
+			return repeatableChecksum(file, normalization)
+		}
+		file := migration.file()
+		if file.Version == nil || fileChecksum(file, normalization) != migration.Checksum {
+			return migration.Checksum
+		}
+		file.Scope = scope
+		return fileChecksum(file, normalization)
+	}
+	return migration.Checksum
+}
@@ -0,0 +1,133 @@
+package vermig
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+const repeatableDir = "repeatable"
+
+func (m *Vermig) collectRepeatable(root fs.FS, source Source, relativePath, logicalPath string) error {
+	fileBytes, readErr := fs.ReadFile(root, relativePath)
+	if readErr != nil {
+		return &ParseError{
+			Path: path.Join(source.Dir, relativePath),
+			Err:  fmt.Errorf("%w: %w", ErrMigrationReadFailed, readErr),
+		}
+	}
+	m.repeatables = append(
+		m.repeatables, File{
+			Scope:      m.scope(source, logicalPath),
+			Name:       path.Base(relativePath),
+			UpPath:     path.Join(source.Dir, relativePath),
+			Up:         string(fileBytes),
+			Directives: parseDirectives(string(fileBytes)),
+		},
+	)
+	return nil
+}
+
+func repeatableChecksum(file File, normalization ChecksumNormalization) string {
+	sum := sha256.Sum256([]byte(file.Scope + "\x00" + file.Name + "\x00" + normalizeChecksumSQL(file.Up, normalization)))
+	return fmt.Sprintf("%s%x", checksumPrefixes[normalization], sum)
+}
+
+func (m *Vermig) findRepeatableChecksums(ctx context.Context, db DB) (map[string]string, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select(m.selectCols("scope", "name", "checksum")...).
+		From(m.table()).
+		Where(m.col("version") + " IS NULL").
+		ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create repeatable migrations sql failed: %w", createSqlErr)
+	}
+	var rows []struct {
+		Scope    string
+		Name     string
+		Checksum string
+	}
+	if err := pgxscan.Select(ctx, db, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("select repeatable migrations failed: %w", err)
+	}
+	checksums := make(map[string]string, len(rows))
+	for _, row := range rows {
+		checksums[row.Scope+"/"+row.Name] = row.Checksum
+	}
+	return checksums, nil
+}
+
+func (m *Vermig) repeatablesPending(ctx context.Context, db DB) (bool, error) {
+	if len(m.repeatables) == 0 {
+		return false, nil
+	}
+	checksums, findChecksumsErr := m.findRepeatableChecksums(ctx, db)
+	if findChecksumsErr != nil {
+		return false, fmt.Errorf("find repeatable checksums failed: %w", findChecksumsErr)
+	}
+	for _, file := range m.repeatables {
+		if checksums[file.Scope+"/"+file.Name] != repeatableChecksum(file, m.checksumNormalization) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *Vermig) migrateRepeatables(ctx context.Context, tx pgx.Tx) ([]Migration, error) {
+	checksums, findChecksumsErr := m.findRepeatableChecksums(ctx, tx)
+	if findChecksumsErr != nil {
+		return nil, fmt.Errorf("find repeatable checksums failed: %w", findChecksumsErr)
+	}
+	var applied []Migration
+	for _, file := range m.repeatables {
+		startedAt := time.Now()
+		checksum := repeatableChecksum(file, m.checksumNormalization)
+		if checksums[file.Scope+"/"+file.Name] == checksum {
+			continue
+		}
+		event := Event{
+			Direction: DirectionUp,
+			Scope:     file.Scope,
+			Name:      file.Name,
+		}
+		query, transformErr := m.transformSQL(file, file.Up)
+		if transformErr != nil {
+			return nil, file.migrationError(transformErr)
+		}
+		if execErr := m.execQuery(ctx, tx, event, query); execErr != nil {
+			return nil, fmt.Errorf("run repeatable migration failed: %w", file.migrationError(execErr))
+		}
+		m.logf(ctx, "🔁 %s/%s: ✅", file.Scope, file.Name)
+		sql, args, createSqlErr := m.statementBuilder.Delete(m.table()).
+			Where(squirrel.Eq{m.col("scope"): file.Scope, m.col("name"): file.Name}).
+			Where(m.col("version") + " IS NULL").
+			ToSql()
+		if createSqlErr != nil {
+			return nil, fmt.Errorf("create repeatable migration delete sql failed: %w", createSqlErr)
+		}
+		if _, execErr := tx.Exec(ctx, sql, args...); execErr != nil {
+			return nil, fmt.Errorf("delete repeatable migration failed: %w", execErr)
+		}
+		migration := Migration{
+			Name:       file.Name,
+			Scope:      file.Scope,
+			Up:         file.Up,
+			Checksum:   checksum,
+			SourcePath: file.UpPath,
+		}
+		if insertMigrationErr := m.insertMigration(ctx, tx, migration, nil); insertMigrationErr != nil {
+			return nil, fmt.Errorf("insert repeatable migration failed: %w", file.migrationError(insertMigrationErr))
+		}
+		applied = append(applied, migration)
+		recordReport(ctx, DirectionUp, file.Scope, file.Name, "", startedAt)
+		event.Type = EventMigrationApplied
+		m.emit(ctx, event)
+	}
+	return applied, nil
+}
@@ -0,0 +1,85 @@
+package vermig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+)
+
+type Report struct {
+	CorrelationID string        `json:"correlation_id"`
+	Status        string        `json:"status"`
+	Error         string        `json:"error,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	ElapsedMs     int64         `json:"elapsed_ms"`
+	Applied       []ReportEntry `json:"applied"`
+	Reverted      []ReportEntry `json:"reverted"`
+}
+
+type ReportEntry struct {
+	Scope     string `json:"scope"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+	Committed bool   `json:"committed"`
+}
+
+type reportKey struct{}
+
+func (m *Vermig) migrate(ctx context.Context, targetVersion *semver.Version, include func(File) bool) error {
+	if m.reportWriter == nil {
+		return m.migrateBatches(ctx, targetVersion, include)
+	}
+	r := &Report{
+		CorrelationID: correlationID(ctx),
+		StartedAt:     time.Now(),
+		Applied:       []ReportEntry{},
+		Reverted:      []ReportEntry{},
+	}
+	err := m.migrateBatches(context.WithValue(ctx, reportKey{}, r), targetVersion, include)
+	r.ElapsedMs = time.Since(r.StartedAt).Milliseconds()
+	r.Status = "success"
+	if err != nil {
+		r.Status = "failed"
+		r.Error = err.Error()
+	}
+	if writeErr := json.NewEncoder(m.reportWriter).Encode(r); writeErr != nil {
+		return errors.Join(err, fmt.Errorf("write report failed: %w", writeErr))
+	}
+	return err
+}
+
+func recordReport(ctx context.Context, direction Direction, scope, name, version string, startedAt time.Time) {
+	r, ok := ctx.Value(reportKey{}).(*Report)
+	if !ok {
+		return
+	}
+	entry := ReportEntry{
+		Scope:     scope,
+		Name:      name,
+		Version:   version,
+		ElapsedMs: time.Since(startedAt).Milliseconds(),
+	}
+	if direction == DirectionDown {
+		r.Reverted = append(r.Reverted, entry)
+		return
+	}
+	r.Applied = append(r.Applied, entry)
+}
+
+func commitReport(ctx context.Context) {
+	r, ok := ctx.Value(reportKey{}).(*Report)
+	if !ok {
+		return
+	}
+	for i := range r.Applied {
+		r.Applied[i].Committed = true
+	}
+	for i := range r.Reverted {
+		r.Reverted[i].Committed = true
+	}
+}
@@ -0,0 +1,52 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) Reset(ctx context.Context) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	return m.reset(ctx)
+}
+
+func (m *Vermig) reset(ctx context.Context) error {
+	if !m.allowDowngrade {
+		return ErrDowngradeNotAllowed
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			migrations, findErr := m.findAllMigrations(ctx, tx)
+			if findErr != nil {
+				return fmt.Errorf("find all migrations failed: %w", findErr)
+			}
+			if err := m.sortDowngrades(migrations); err != nil {
+				return fmt.Errorf("sort migrations failed: %w", err)
+			}
+			if err := m.migrateDown(ctx, tx, migrations); err != nil {
+				return fmt.Errorf("downgrade db failed: %w", err)
+			}
+			if err := m.syncVersionTable(ctx, tx); err != nil {
+				return fmt.Errorf("sync version table failed: %w", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return fmt.Errorf("reset failed: %w", err)
+	}
+	m.logf(ctx, "migrator status: ✅")
+	return nil
+}
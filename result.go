@@ -0,0 +1,35 @@
+package vermig
+
+import (
+	"context"
+	"sort"
+)
+
+type MigrateResult struct {
+	Applied  []Migration
+	Reverted []Migration
+	Scopes   []string
+}
+
+type resultKey struct{}
+
+func (m *Vermig) MigrateWithReport(ctx context.Context, version string) (MigrateResult, error) {
+	result := new(MigrateResult)
+	err := m.Migrate(context.WithValue(ctx, resultKey{}, result), version)
+	scopes := make(map[string]struct{})
+	for _, migration := range append(append([]Migration{}, result.Applied...), result.Reverted...) {
+		scopes[migration.Scope] = struct{}{}
+	}
+	for scope := range scopes {
+		result.Scopes = append(result.Scopes, scope)
+	}
+	sort.Strings(result.Scopes)
+	return *result, err
+}
+
+func recordResult(ctx context.Context, applied, reverted []Migration) {
+	if result, ok := ctx.Value(resultKey{}).(*MigrateResult); ok {
+		result.Applied = append(result.Applied, applied...)
+		result.Reverted = append(result.Reverted, reverted...)
+	}
+}
@@ -0,0 +1,24 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const roleDirective = "role"
+
+func execAsRole(ctx context.Context, tx pgx.Tx, role string, fn func() error) error {
+	if role == "" {
+		return fn()
+	}
+	restore, setRoleErr := setLocal(ctx, tx, "role", role)
+	if setRoleErr != nil {
+		return fmt.Errorf("set role %s failed: %w", role, setRoleErr)
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return restore()
+}
@@ -0,0 +1,20 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) runSeeds(ctx context.Context, tx pgx.Tx) error {
+	for i, seed := range m.seeds {
+		if err := seed(ctx, tx); err != nil {
+			return fmt.Errorf("seed %d failed: %w", i+1, err)
+		}
+	}
+	if len(m.seeds) > 0 {
+		m.logf(ctx, "🌱 seeds: ✅")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func setLocal(ctx context.Context, tx pgx.Tx, name, value string) (func() error, error) {
+	var previous string
+	if err := tx.QueryRow(ctx, "SELECT current_setting($1)", name).Scan(&previous); err != nil {
+		return nil, fmt.Errorf("get %s setting failed: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT set_config($1, $2, true)", name, value); err != nil {
+		return nil, fmt.Errorf("set %s setting failed: %w", name, err)
+	}
+	return func() error {
+		if _, err := tx.Exec(ctx, "SELECT set_config($1, $2, true)", name, previous); err != nil {
+			return fmt.Errorf("restore %s setting failed: %w", name, err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,59 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var createdAtLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+func (m *Vermig) MigrateSince(ctx context.Context, since time.Time) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	if len(m.files) == 0 {
+		return ErrNoMigrationsFound
+	}
+	included := make(map[string]struct{}, len(m.files))
+	for _, file := range m.files {
+		createdAt, ok, parseErr := file.createdAt()
+		if parseErr != nil {
+			return &ParseError{Path: file.UpPath, Err: fmt.Errorf("parse created directive failed: %w", parseErr)}
+		}
+		if ok && !createdAt.Before(since) {
+			included[file.key()] = struct{}{}
+		}
+	}
+	return m.migrate(
+		ctx, m.latestVersion(), func(file File) bool {
+			_, ok := included[file.key()]
+			return ok
+		},
+	)
+}
+
+func (f File) createdAt() (time.Time, bool, error) {
+	raw := f.Directives.Get("created")
+	if raw == "" {
+		return time.Time{}, false, nil
+	}
+	var lastErr error
+	for _, layout := range createdAtLayouts {
+		createdAt, parseErr := time.Parse(layout, raw)
+		if parseErr == nil {
+			return createdAt, true, nil
+		}
+		lastErr = parseErr
+	}
+	return time.Time{}, false, lastErr
+}
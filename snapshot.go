@@ -0,0 +1,120 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+const snapshotSchema = "vermig_snapshot"
+
+const schemaDumpQuery = `WITH relations AS (
+    SELECT c.oid, c.relname, c.relkind
+    FROM pg_class c
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'S', 'f')
+)
+SELECT 'relation ' || relname || ' (' || relkind || ')' FROM relations
+UNION ALL
+SELECT 'column ' || r.relname || '.' || a.attname || ' ' || format_type(a.atttypid, a.atttypmod)
+    || CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+    || COALESCE(' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid), '')
+FROM relations r
+JOIN pg_attribute a ON a.attrelid = r.oid
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attnum > 0 AND NOT a.attisdropped
+UNION ALL
+SELECT 'constraint ' || r.relname || '.' || c.conname || ' ' || pg_get_constraintdef(c.oid)
+FROM pg_constraint c
+JOIN relations r ON r.oid = c.conrelid
+UNION ALL
+SELECT 'index ' || indexname || ' ' || replace(indexdef, quote_ident($1) || '.', '')
+FROM pg_indexes
+WHERE schemaname = $1
+UNION ALL
+SELECT 'view ' || r.relname || ' ' || pg_get_viewdef(r.oid)
+FROM relations r
+WHERE r.relkind IN ('v', 'm')
+UNION ALL
+SELECT 'function ' || p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ') ' || pg_get_function_result(p.oid)
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname = $1 AND p.prokind IN ('f', 'p')
+UNION ALL
+SELECT 'type ' || t.typname || ' ' || CASE t.typtype
+    WHEN 'e' THEN '(' || (SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = t.oid) || ')'
+    ELSE format_type(t.typbasetype, t.typtypmod)
+END
+FROM pg_type t
+JOIN pg_namespace n ON n.oid = t.typnamespace
+WHERE n.nspname = $1 AND t.typtype IN ('e', 'd')
+ORDER BY 1;`
+
+func (m *Vermig) ApplyToSnapshot(ctx context.Context, baseSQL string, version string) (string, error) {
+	ctx = m.withCorrelationID(ctx)
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return "", fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	pv, resolveVersionErr := m.resolveTargetVersion(version)
+	if resolveVersionErr != nil {
+		return "", fmt.Errorf("parse version failed: %w", resolveVersionErr)
+	}
+	if m.pinnedVersion != nil && pv.GreaterThan(m.pinnedVersion) {
+		return "", fmt.Errorf("%w: %s is above %s", ErrVersionPinned, pv, m.pinnedVersion)
+	}
+	tx, beginErr := m.begin(ctx)
+	if beginErr != nil {
+		return "", fmt.Errorf("begin snapshot failed: %w", beginErr)
+	}
+	result, applyErr := m.applyToSnapshot(ctx, tx, baseSQL, pv)
+	if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+		return "", errors.Join(fmt.Errorf("rollback snapshot failed: %w", rollbackErr), applyErr)
+	}
+	return result, applyErr
+}
+
+func (m *Vermig) applyToSnapshot(ctx context.Context, tx pgx.Tx, baseSQL string, targetVersion *semver.Version) (string, error) {
+	schema := pgx.Identifier{snapshotSchema}.Sanitize()
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		return "", fmt.Errorf("create snapshot schema failed: %w", err)
+	}
+	if _, err := setLocal(ctx, tx, "search_path", schema); err != nil {
+		return "", fmt.Errorf("set search path failed: %w", err)
+	}
+	if strings.TrimSpace(baseSQL) != "" {
+		if _, err := tx.Exec(ctx, baseSQL); err != nil {
+			return "", fmt.Errorf("apply base schema failed: %w", err)
+		}
+	}
+	if err := m.runPreamble(ctx, tx); err != nil {
+		return "", fmt.Errorf("run preamble failed: %w", err)
+	}
+	for _, file := range m.files {
+		if file.Version.GreaterThan(targetVersion) || (m.skipPredicate != nil && m.skipPredicate(file)) {
+			continue
+		}
+		query, transformErr := m.transformSQL(file, file.Up)
+		if transformErr != nil {
+			return "", file.migrationError(transformErr)
+		}
+		if execErr := execAsRole(
+			ctx, tx, file.Directives.Get(roleDirective), func() error {
+				return validateQuery(ctx, tx, query)
+			},
+		); execErr != nil {
+			return "", fmt.Errorf("run migration up failed: %w", file.migrationError(execErr))
+		}
+		m.logf(ctx, "📸 %s/%s: ✅", file.Scope, file.Name)
+	}
+	var objects []string
+	if err := pgxscan.Select(ctx, tx, &objects, schemaDumpQuery, snapshotSchema); err != nil {
+		return "", fmt.Errorf("dump snapshot schema failed: %w", err)
+	}
+	return strings.Join(objects, "\n"), nil
+}
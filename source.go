@@ -0,0 +1,165 @@
+package vermig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const (
+	layoutUpDir   = "up"
+	layoutDownDir = "down"
+)
+
+type Source struct {
+	FS         fs.FS
+	Dir        string
+	UpSuffix   string
+	DownSuffix string
+	Scope      func(path string) string
+}
+
+func (s Source) withDefaults() Source {
+	s.Dir = path.Clean("/" + s.Dir)[1:]
+	if s.Dir == "" {
+		s.Dir = "."
+	}
+	if s.UpSuffix == "" {
+		s.UpSuffix = "_up.sql"
+	}
+	if s.DownSuffix == "" {
+		s.DownSuffix = "_down.sql"
+	}
+	return s
+}
+
+func (m *Vermig) scope(source Source, relativePath string) string {
+	if source.Scope != nil {
+		return source.Scope(relativePath)
+	}
+	if dir := path.Dir(relativePath); dir != "." {
+		return dir
+	}
+	return m.rootScope
+}
+
+type Layout int
+
+const (
+	LayoutSuffix Layout = iota
+	LayoutDirectories
+)
+
+func (m *Vermig) layoutPaths(source Source, relativePath string) (string, string, bool) {
+	if m.layout == LayoutDirectories {
+		logicalPath, isUp := strings.CutPrefix(relativePath, layoutUpDir+"/")
+		if !isUp || !strings.HasSuffix(logicalPath, ".sql") {
+			return "", "", false
+		}
+		return logicalPath, layoutDownDir + "/" + logicalPath, true
+	}
+	name := path.Base(relativePath)
+	if strings.HasSuffix(name, source.DownSuffix) || !strings.HasSuffix(name, source.UpSuffix) {
+		return "", "", false
+	}
+	return relativePath, strings.TrimSuffix(relativePath, source.UpSuffix) + source.DownSuffix, true
+}
+
+func (m *Vermig) isDownFile(source Source, relativePath string) bool {
+	if m.layout == LayoutDirectories {
+		return strings.HasPrefix(relativePath, layoutDownDir+"/") && strings.HasSuffix(relativePath, ".sql")
+	}
+	return strings.HasSuffix(path.Base(relativePath), source.DownSuffix)
+}
+
+func defaultVersionParser(filename string) (string, error) {
+	separator := strings.Index(filename, "_")
+	if separator < 0 {
+		return "", fmt.Errorf("missing version separator")
+	}
+	return filename[:separator], nil
+}
+
+func (m *Vermig) collectSource(source Source) error {
+	source = source.withDefaults()
+	root, subErr := fs.Sub(source.FS, source.Dir)
+	if subErr != nil {
+		return fmt.Errorf("open %s migrations dir failed: %w", source.Dir, subErr)
+	}
+	var downPaths []string
+	pairedDownPaths := make(map[string]struct{})
+	walkErr := fs.WalkDir(
+		root, ".", func(relativePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return &ParseError{Path: path.Join(source.Dir, relativePath), Err: err}
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			name := entry.Name()
+			logicalPath, relativeDownPath, isUp := m.layoutPaths(source, relativePath)
+			if !isUp {
+				if m.isDownFile(source, relativePath) {
+					downPaths = append(downPaths, relativePath)
+				}
+				return nil
+			}
+			pairedDownPaths[relativeDownPath] = struct{}{}
+			if strings.HasPrefix(logicalPath, repeatableDir+"/") {
+				return m.collectRepeatable(root, source, relativePath, logicalPath)
+			}
+			p, downPath := path.Join(source.Dir, relativePath), path.Join(source.Dir, relativeDownPath)
+			versionParser := m.versionParser
+			if versionParser == nil {
+				versionParser = defaultVersionParser
+			}
+			rawVersion, extractVersionErr := versionParser(name)
+			if extractVersionErr != nil {
+				return &ParseError{Path: p, Err: fmt.Errorf("extract version failed: %w", extractVersionErr)}
+			}
+			pv, step, parseVersionErr := m.parseOrderedVersion(rawVersion)
+			if parseVersionErr != nil {
+				return &ParseError{Path: p, Err: fmt.Errorf("parse version failed: %w", parseVersionErr)}
+			}
+			fileBytes, readMigrationUpErr := fs.ReadFile(root, relativePath)
+			if readMigrationUpErr != nil {
+				return &ParseError{Path: p, Err: fmt.Errorf("%w: %w", ErrMigrationReadFailed, readMigrationUpErr)}
+			}
+			var queryDown string
+			downFileBytes, readMigrationDownErr := fs.ReadFile(root, relativeDownPath)
+			switch {
+			case readMigrationDownErr == nil:
+				queryDown = string(downFileBytes)
+			case !errors.Is(readMigrationDownErr, fs.ErrNotExist):
+				return &ParseError{Path: downPath, Err: fmt.Errorf("%w: %w", ErrMigrationReadFailed, readMigrationDownErr)}
+			}
+			m.files = append(
+				m.files, File{
+					Priority:   m.parsePriority(logicalPath),
+					Version:    pv,
+					Step:       step,
+					Scope:      m.scope(source, logicalPath),
+					Name:       name,
+					UpPath:     p,
+					DownPath:   downPath,
+					Up:         string(fileBytes),
+					Down:       queryDown,
+					Directives: parseDirectives(string(fileBytes)),
+					downErr:    readMigrationDownErr,
+				},
+			)
+			return nil
+		},
+	)
+	if walkErr != nil {
+		return walkErr
+	}
+	for _, downPath := range downPaths {
+		if _, paired := pairedDownPaths[downPath]; !paired {
+			return &ParseError{Path: path.Join(source.Dir, downPath), Err: ErrOrphanedDownFile}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package vermig
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestScope(t *testing.T) {
+	files := fstest.MapFS{
+		"1.0.0_root_up.sql":               {Data: []byte("SELECT 1;")},
+		"users/1.1.0_users_up.sql":        {Data: []byte("SELECT 1;")},
+		"users/admin/1.2.0_admins_up.sql": {Data: []byte("SELECT 1;")},
+	}
+	tests := []struct {
+		name      string
+		source    Source
+		rootScope string
+		want      map[string]string
+	}{
+		{
+			name:   "root files get the empty scope by default",
+			source: Source{FS: files},
+			want: map[string]string{
+				"1.0.0_root_up.sql": "", "1.1.0_users_up.sql": "users", "1.2.0_admins_up.sql": "users/admin",
+			},
+		},
+		{
+			name:      "root files get WithRootScope",
+			source:    Source{FS: files},
+			rootScope: "core",
+			want: map[string]string{
+				"1.0.0_root_up.sql": "core", "1.1.0_users_up.sql": "users", "1.2.0_admins_up.sql": "users/admin",
+			},
+		},
+		{
+			name:      "root of a source dir gets WithRootScope",
+			source:    Source{FS: files, Dir: "users"},
+			rootScope: "core",
+			want:      map[string]string{"1.1.0_users_up.sql": "core", "1.2.0_admins_up.sql": "admin"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(
+			test.name, func(t *testing.T) {
+				m := newVermig(WithSource(test.source), WithRootScope(test.rootScope))
+				m, collectFilesErr := m.collectFiles()
+				if collectFilesErr != nil {
+					t.Fatalf("collect migrations failed: %s", collectFilesErr)
+				}
+				got := make(map[string]string, len(m.files))
+				for _, file := range m.files {
+					got[file.Name] = file.Scope
+				}
+				if len(got) != len(test.want) {
+					t.Errorf("got %v, want %v", got, test.want)
+				}
+				for name, scope := range test.want {
+					if got[name] != scope {
+						t.Errorf("%s: got scope %q, want %q", name, got[name], scope)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestAdoptLegacyRootScopes(t *testing.T) {
+	db := testDB(t)
+	files := testFiles("1.0.0")
+	testVermig(t, db, files)
+	name := "1.0.0_create-t_1_0_0_up.sql"
+	up, down := string(files[name].Data), string(files["1.0.0_create-t_1_0_0_down.sql"].Data)
+	if _, err := db.Exec(
+		t.Context(),
+		`INSERT INTO migrations (name, version, major, minor, patch, prerelease, step, scope, up, down, checksum)
+VALUES ($1, '1.0.0', 1, 0, 0, '', 0, $1, $2, $3, $4)`, name, up, down, createChecksum(up, down),
+	); err != nil {
+		t.Fatalf("insert legacy row failed: %s", err)
+	}
+	m := testVermig(t, db, files)
+	if err := m.Migrate(t.Context(), "1.0.0"); err != nil {
+		t.Fatalf("migrate failed: %s", err)
+	}
+	var scope string
+	var rerun bool
+	if err := db.QueryRow(
+		t.Context(), "SELECT scope, to_regclass('t_1_0_0') IS NOT NULL FROM migrations WHERE name = $1", name,
+	).Scan(&scope, &rerun); err != nil {
+		t.Fatalf("select legacy row failed: %s", err)
+	}
+	if scope != "" {
+		t.Errorf("scope: got %q, want the root scope", scope)
+	}
+	if rerun {
+		t.Error("legacy root migration ran again")
+	}
+}
@@ -0,0 +1,76 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) SQL(ctx context.Context, version string) (string, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return "", fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	pv, resolveVersionErr := m.resolveTargetVersion(version)
+	if resolveVersionErr != nil {
+		return "", fmt.Errorf("parse version failed: %w", resolveVersionErr)
+	}
+	if m.pinnedVersion != nil && pv.GreaterThan(m.pinnedVersion) {
+		return "", fmt.Errorf("%w: %s is above %s", ErrVersionPinned, pv, m.pinnedVersion)
+	}
+	p, buildPlanErr := m.buildPlan(ctx, m.db, pv, nil)
+	if buildPlanErr != nil {
+		return "", fmt.Errorf("build plan failed: %w", buildPlanErr)
+	}
+	return m.planSQL(p)
+}
+
+func (m *Vermig) planSQL(p plan) (string, error) {
+	if len(p.down) > 0 && m.withoutDownSQL {
+		return "", fmt.Errorf("%w: downgrades need WithStoreDownSQL(true)", ErrDownSQLNotStored)
+	}
+	var sb strings.Builder
+	if m.preamble != "" && len(p.down)+len(p.up) > 0 {
+		sb.WriteString("-- preamble\n" + strings.TrimSpace(m.preamble) + "\n")
+	}
+	for _, migration := range p.down {
+		query, transformErr := m.transformSQL(migration.file(), migration.Down)
+		if transformErr != nil {
+			return "", migration.migrationError(transformErr)
+		}
+		query = withRole(parseDirectives(migration.Down).Get(roleDirective), query)
+		writeSQLSection(&sb, "down", migration.Scope, migration.Name, query)
+	}
+	for _, file := range p.up {
+		query, transformErr := m.transformSQL(file, file.Up)
+		if transformErr != nil {
+			return "", file.migrationError(transformErr)
+		}
+		query = withRole(file.Directives.Get(roleDirective), query)
+		if file.Directives.Has("preamble") && m.preamble != "" {
+			query = strings.TrimSpace(m.preamble) + "\n" + query
+		}
+		writeSQLSection(&sb, "up", file.Scope, file.Name, query)
+	}
+	return sb.String(), nil
+}
+
+func withRole(role, query string) string {
+	if role == "" {
+		return query
+	}
+	return fmt.Sprintf(
+		"SET LOCAL ROLE %s;\n%s\nSET LOCAL ROLE NONE;", pgx.Identifier{role}.Sanitize(), strings.TrimSpace(query),
+	)
+}
+
+func writeSQLSection(sb *strings.Builder, direction, scope, name, query string) {
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(sb, "-- %s: %s/%s\n", direction, scope, name)
+	sb.WriteString(strings.TrimSpace(query))
+	sb.WriteString("\n")
+}
@@ -0,0 +1,57 @@
+package vermig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+)
+
+func TestPlanSQL(t *testing.T) {
+	m := newVermig(
+		WithPreamble("SET LOCAL timezone = 'UTC'"),
+		WithSQLTransform(
+			func(file File, sql string) (string, error) {
+				return strings.ReplaceAll(sql, "{{SCHEMA}}", "tenant_42"), nil
+			},
+		),
+	)
+	files := unitFiles("1.0.0", "1.1.0")
+	files[0].Up = "CREATE TABLE {{SCHEMA}}.a (id int);"
+	files[1].Up = "-- vermig:role=migrator\n-- vermig:preamble\nCREATE TABLE {{SCHEMA}}.b (id int);"
+	files[1].Directives = parseDirectives(files[1].Up)
+	down := Migration{Scope: "app", Name: "2.0.0_create", Version: "2.0.0", Down: "DROP TABLE {{SCHEMA}}.c;"}
+	got, planSQLErr := m.planSQL(plan{down: []Migration{down}, up: files})
+	if planSQLErr != nil {
+		t.Fatalf("plan sql failed: %s", planSQLErr)
+	}
+	want := `-- preamble
+SET LOCAL timezone = 'UTC'
+
+-- down: app/2.0.0_create
+DROP TABLE tenant_42.c;
+
+-- up: app/1.0.0_create
+CREATE TABLE tenant_42.a (id int);
+
+-- up: app/1.1.0_create
+SET LOCAL timezone = 'UTC'
+SET LOCAL ROLE "migrator";
+-- vermig:role=migrator
+-- vermig:preamble
+CREATE TABLE tenant_42.b (id int);
+SET LOCAL ROLE NONE;
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSQLPinnedVersion(t *testing.T) {
+	m := newVermig(WithFS(testFiles("1.0.0", "2.0.0")))
+	m.pinnedVersion = semver.MustParse("1.0.0")
+	if _, err := m.SQL(t.Context(), "2.0.0"); !errors.Is(err, ErrVersionPinned) {
+		t.Errorf("got %v, want %v", err, ErrVersionPinned)
+	}
+}
@@ -0,0 +1,30 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (m *Vermig) Squash(ctx context.Context, upTo string) (string, error) {
+	pv, parseVersionErr := parseVersion(upTo)
+	if parseVersionErr != nil {
+		return "", fmt.Errorf("parse version failed: %w", parseVersionErr)
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return "", fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	var sb strings.Builder
+	for _, file := range m.files {
+		if file.Version.GreaterThan(pv) {
+			continue
+		}
+		query := strings.TrimSpace(file.Up)
+		if code := strings.TrimSpace(stripComments(query)); code != "" && !strings.HasSuffix(code, ";") {
+			query += "\n;"
+		}
+		writeSQLSection(&sb, "up", file.Scope, file.Name, query)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,149 @@
+package vermig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const stateFormatVersion = 1
+
+type stateManifest struct {
+	Format     int              `json:"format"`
+	Migrations []stateMigration `json:"migrations"`
+}
+
+type stateMigration struct {
+	Scope      string    `json:"scope"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	Step       int       `json:"step"`
+	Up         string    `json:"up"`
+	Down       string    `json:"down"`
+	Checksum   string    `json:"checksum"`
+	SourcePath string    `json:"source_path"`
+	Tags       string    `json:"tags"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (m *Vermig) ExportState(ctx context.Context, w io.Writer) error {
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	if err := sortMigrationsDesc(migrations); err != nil {
+		return fmt.Errorf("sort migrations failed: %w", err)
+	}
+	manifest := stateManifest{Format: stateFormatVersion, Migrations: make([]stateMigration, 0, len(migrations))}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		manifest.Migrations = append(
+			manifest.Migrations, stateMigration{
+				Scope:      migration.Scope,
+				Name:       migration.Name,
+				Version:    migration.Version,
+				Step:       migration.Step,
+				Up:         migration.Up,
+				Down:       migration.Down,
+				Checksum:   migration.Checksum,
+				SourcePath: migration.SourcePath,
+				Tags:       migration.Tags,
+				CreatedAt:  migration.CreatedAt,
+			},
+		)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("write state failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Vermig) ImportState(ctx context.Context, r io.Reader) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	var manifest stateManifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return fmt.Errorf("read state failed: %w", err)
+	}
+	if manifest.Format != stateFormatVersion {
+		return fmt.Errorf("unsupported state format %d", manifest.Format)
+	}
+	imported := make([]Migration, 0, len(manifest.Migrations))
+	keys := make(map[string]struct{}, len(manifest.Migrations))
+	for _, entry := range manifest.Migrations {
+		pv, parseVersionErr := parseVersion(entry.Version)
+		if parseVersionErr != nil {
+			return fmt.Errorf("parse %s/%s version failed: %w", entry.Scope, entry.Name, parseVersionErr)
+		}
+		migration := Migration{
+			Name:       entry.Name,
+			Version:    pv.String(),
+			Major:      pv.Major(),
+			Minor:      pv.Minor(),
+			Patch:      pv.Patch(),
+			Prerelease: pv.Prerelease(),
+			Step:       entry.Step,
+			Scope:      entry.Scope,
+			Up:         entry.Up,
+			Down:       entry.Down,
+			Checksum:   entry.Checksum,
+			SourcePath: entry.SourcePath,
+			Tags:       entry.Tags,
+			CreatedAt:  entry.CreatedAt,
+		}
+		if _, exists := keys[migration.key()]; exists {
+			return fmt.Errorf("%w: %s appears twice in state", ErrStateConflict, migration.key())
+		}
+		keys[migration.key()] = struct{}{}
+		imported = append(imported, migration)
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			if err := m.lockTable(ctx, tx); err != nil {
+				return fmt.Errorf("lock migrations table failed: %w", err)
+			}
+			migrations, findErr := m.findAllMigrations(ctx, tx)
+			if findErr != nil {
+				return fmt.Errorf("find all migrations failed: %w", findErr)
+			}
+			existing := make(map[string]Migration, len(migrations))
+			for _, migration := range migrations {
+				existing[migration.key()] = migration
+			}
+			pending := make([]Migration, 0, len(imported))
+			for _, migration := range imported {
+				current, exists := existing[migration.key()]
+				if !exists {
+					pending = append(pending, migration)
+					continue
+				}
+				if current.Checksum != migration.Checksum {
+					return fmt.Errorf("%w: %s has a different checksum", ErrStateConflict, migration.key())
+				}
+			}
+			if err := m.insertMigrations(ctx, tx, pending, make([]map[string]any, len(pending))); err != nil {
+				return fmt.Errorf("insert migrations failed: %w", err)
+			}
+			if err := m.syncVersionTable(ctx, tx); err != nil {
+				return fmt.Errorf("sync version table failed: %w", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return fmt.Errorf("import state failed: %w", err)
+	}
+	m.logf(ctx, "migrator status: ✅")
+	return nil
+}
@@ -0,0 +1,176 @@
+package vermig
+
+import (
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	segmentCode segmentKind = iota
+	segmentComment
+	segmentQuoted
+)
+
+type sqlSegment struct {
+	kind segmentKind
+	text string
+}
+
+func scanSQL(query string) []sqlSegment {
+	var segments []sqlSegment
+	push := func(kind segmentKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && kind == segmentCode && segments[n-1].kind == segmentCode {
+			segments[n-1].text += text
+			return
+		}
+		segments = append(segments, sqlSegment{kind: kind, text: text})
+	}
+	start := 0
+	for i := 0; i < len(query); {
+		var end int
+		var kind segmentKind
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			end = strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+			kind = segmentComment
+		case strings.HasPrefix(query[i:], "/*"):
+			end = scanBlockComment(query, i)
+			kind = segmentComment
+		case query[i] == '\'':
+			escapes := i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i < 2 || !isIdentChar(query[i-2]))
+			end = scanQuoted(query, i, '\'', escapes)
+			kind = segmentQuoted
+		case query[i] == '"':
+			end = scanQuoted(query, i, '"', false)
+			kind = segmentQuoted
+		case query[i] == '$' && (i == 0 || !isIdentChar(query[i-1])):
+			tag, ok := dollarTag(query[i:])
+			if !ok {
+				i++
+				continue
+			}
+			closing := strings.Index(query[i+len(tag):], tag)
+			if closing < 0 {
+				end = len(query)
+			} else {
+				end = i + len(tag) + closing + len(tag)
+			}
+			kind = segmentQuoted
+		default:
+			i++
+			continue
+		}
+		push(segmentCode, query[start:i])
+		push(kind, query[i:end])
+		i, start = end, end
+	}
+	push(segmentCode, query[start:])
+	return segments
+}
+
+func scanBlockComment(query string, i int) int {
+	depth := 0
+	for j := i; j < len(query)-1; j++ {
+		switch query[j : j+2] {
+		case "/*":
+			depth++
+			j++
+		case "*/":
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(query)
+}
+
+func scanQuoted(query string, i int, quote byte, escapes bool) int {
+	for j := i + 1; j < len(query); j++ {
+		switch {
+		case escapes && query[j] == '\\':
+			j++
+		case query[j] == quote && j+1 < len(query) && query[j+1] == quote:
+			j++
+		case query[j] == quote:
+			return j + 1
+		}
+	}
+	return len(query)
+}
+
+func dollarTag(query string) (string, bool) {
+	for j := 1; j < len(query); j++ {
+		if query[j] == '$' {
+			return query[:j+1], true
+		}
+		if !isIdentChar(query[j]) || (j == 1 && query[j] >= '0' && query[j] <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func splitStatements(query string) []string {
+	var statements []string
+	var current strings.Builder
+	hasCode := false
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasCode = false
+	}
+	for _, segment := range scanSQL(query) {
+		if segment.kind != segmentCode {
+			current.WriteString(segment.text)
+			hasCode = hasCode || segment.kind == segmentQuoted
+			continue
+		}
+		parts := strings.Split(segment.text, ";")
+		for i, part := range parts {
+			if i > 0 {
+				flush()
+			}
+			current.WriteString(part)
+			hasCode = hasCode || strings.TrimSpace(part) != ""
+		}
+	}
+	flush()
+	return statements
+}
+
+func stripComments(query string) string {
+	var sb strings.Builder
+	for _, segment := range scanSQL(query) {
+		if segment.kind == segmentComment {
+			sb.WriteString(" ")
+			continue
+		}
+		sb.WriteString(segment.text)
+	}
+	return sb.String()
+}
+
+func statementKeyword(statement string) string {
+	fields := strings.Fields(stripComments(statement))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
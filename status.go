@@ -0,0 +1,67 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type MigrationStatus struct {
+	Scope     string
+	Name      string
+	Version   string
+	Step      int
+	Tags      []string
+	Applied   bool
+	Orphaned  bool
+	AppliedAt time.Time
+}
+
+func (m *Vermig) Status(ctx context.Context) ([]MigrationStatus, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return nil, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return nil, fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	applied := make(map[string]Migration, len(migrations))
+	for _, migration := range migrations {
+		applied[migration.key()] = migration
+	}
+	result := make([]MigrationStatus, 0, len(m.files)+len(migrations))
+	for _, file := range m.files {
+		status := MigrationStatus{
+			Scope:   file.Scope,
+			Name:    file.Name,
+			Version: file.Version.String(),
+			Step:    file.Step,
+			Tags:    file.Tags(),
+		}
+		if migration, exists := applied[file.key()]; exists {
+			status.Applied = true
+			status.AppliedAt = migration.CreatedAt
+			delete(applied, file.key())
+		}
+		result = append(result, status)
+	}
+	for _, migration := range migrations {
+		if _, exists := applied[migration.key()]; !exists {
+			continue
+		}
+		result = append(
+			result, MigrationStatus{
+				Scope:     migration.Scope,
+				Name:      migration.Name,
+				Version:   migration.Version,
+				Step:      migration.Step,
+				Tags:      splitTags(migration.Tags),
+				Applied:   true,
+				Orphaned:  true,
+				AppliedAt: migration.CreatedAt,
+			},
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) DownSteps(ctx context.Context, scope string, n int) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	if !m.allowDowngrade {
+		return ErrDowngradeNotAllowed
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	if n <= 0 {
+		return nil
+	}
+	if err := m.inTx(
+		ctx, func(tx pgx.Tx) error {
+			migrations, findErr := m.findScopeMigrations(ctx, tx, scope)
+			if findErr != nil {
+				return fmt.Errorf("find scope migrations failed: %w", findErr)
+			}
+			if err := m.sortDowngrades(migrations); err != nil {
+				return fmt.Errorf("sort migrations failed: %w", err)
+			}
+			if len(migrations) > n {
+				migrations = migrations[:n]
+			}
+			if err := m.migrateDown(ctx, tx, migrations); err != nil {
+				return fmt.Errorf("downgrade db failed: %w", err)
+			}
+			if err := m.syncVersionTable(ctx, tx); err != nil {
+				return fmt.Errorf("sync version table failed: %w", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return fmt.Errorf("down steps failed: %w", err)
+	}
+	m.logf(ctx, "migrator status: ✅")
+	return nil
+}
+
+func (m *Vermig) findScopeMigrations(ctx context.Context, db DB, scope string) ([]Migration, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select().
+		Columns(m.selectCols(migrationColumns...)...).
+		From(m.table()).
+		Where(squirrel.Eq{m.col("scope"): scope}).
+		Where(m.col("version") + " IS NOT NULL").
+		ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create scope migrations sql failed: %w", createSqlErr)
+	}
+	var result []Migration
+	if err := pgxscan.Select(ctx, db, &result, sql, args...); err != nil {
+		return nil, fmt.Errorf("select scope migrations failed: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package vermig
+
+import (
+	"context"
+	"strings"
+)
+
+const tagsDirective = "tags"
+
+func (f File) Tags() []string {
+	var tags []string
+	for _, value := range f.Directives[tagsDirective] {
+		tags = append(tags, splitTags(value)...)
+	}
+	return tags
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (m *Vermig) MigrateTagged(ctx context.Context, version string, tags ...string) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	wanted := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		wanted[tag] = struct{}{}
+	}
+	return m.migrateTo(
+		ctx, version, func(file File) bool {
+			for _, tag := range file.Tags() {
+				if _, exists := wanted[tag]; exists {
+					return true
+				}
+			}
+			return false
+		},
+	)
+}
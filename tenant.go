@@ -0,0 +1,81 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) MigrateTenant(ctx context.Context, schema, version string) error {
+	if err := m.enter(); err != nil {
+		return err
+	}
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if schema == "" {
+		return ErrTenantSchemaRequired
+	}
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	tenant := m.tenant(schema)
+	if err := tenant.ensureTable(ctx); err != nil {
+		return fmt.Errorf("ensure %s migrations table failed: %w", schema, err)
+	}
+	if err := tenant.readIDType(ctx, tenant.db); err != nil {
+		return fmt.Errorf("check %s migrations table failed: %w", schema, err)
+	}
+	if err := tenant.migrateTo(ctx, version, nil); err != nil {
+		return fmt.Errorf("migrate tenant %s failed: %w", schema, err)
+	}
+	return nil
+}
+
+func (m *Vermig) tenant(schema string) *Vermig {
+	tenant := *m
+	tenant.schema = schema
+	tenant.files = nil
+	tenant.repeatables = nil
+	tenant.freshTable = new(atomic.Bool)
+	return &tenant
+}
+
+func (m *Vermig) table() string {
+	switch {
+	case m.schema != "":
+		return pgx.Identifier{m.schema, "migrations"}.Sanitize()
+	case m.managedSchema != "":
+		return pgx.Identifier{m.managedSchema, "migrations"}.Sanitize()
+	}
+	return "migrations"
+}
+
+func (m *Vermig) tableSchema() string {
+	switch {
+	case m.schema != "":
+		return m.schema
+	case m.managedSchema != "":
+		return m.managedSchema
+	}
+	return "public"
+}
+
+func (m *Vermig) createSchema(ctx context.Context, db DB) error {
+	schema := m.tableSchema()
+	if _, err := db.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return fmt.Errorf("create schema %s failed: %w", schema, err)
+	}
+	return nil
+}
+
+func (m *Vermig) setSearchPath(ctx context.Context, tx pgx.Tx) error {
+	if m.schema == "" {
+		return nil
+	}
+	if _, err := setLocal(ctx, tx, "search_path", pgx.Identifier{m.schema}.Sanitize()); err != nil {
+		return fmt.Errorf("set search path failed: %w", err)
+	}
+	return nil
+}
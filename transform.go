@@ -0,0 +1,14 @@
+package vermig
+
+import "fmt"
+
+func (m *Vermig) transformSQL(file File, query string) (string, error) {
+	if m.sqlTransform == nil {
+		return query, nil
+	}
+	transformed, err := m.sqlTransform(file, query)
+	if err != nil {
+		return "", fmt.Errorf("transform %s failed: %w", file.Name, err)
+	}
+	return transformed, nil
+}
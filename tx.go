@@ -0,0 +1,66 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+func (m *Vermig) begin(ctx context.Context) (pgx.Tx, error) {
+	return m.beginWith(ctx, m.txOptions)
+}
+
+func (m *Vermig) beginWith(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	tx, beginErr := m.beginTx(ctx, txOptions)
+	if beginErr != nil {
+		return nil, beginErr
+	}
+	if m.applicationName != "" {
+		if _, err := setLocal(ctx, tx, "application_name", m.applicationName); err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return nil, errors.Join(fmt.Errorf("rollback failed: %w", rollbackErr), err)
+			}
+			return nil, fmt.Errorf("set application name failed: %w", err)
+		}
+	}
+	return tx, nil
+}
+
+func (m *Vermig) beginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if txOptions == (pgx.TxOptions{}) {
+		return m.db.Begin(ctx)
+	}
+	beginner, ok := m.db.(txBeginner)
+	if !ok {
+		return nil, ErrTxOptionsUnsupported
+	}
+	return beginner.BeginTx(ctx, txOptions)
+}
+
+func (m *Vermig) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return m.inTxWith(ctx, m.txOptions, fn)
+}
+
+func (m *Vermig) inTxWith(ctx context.Context, txOptions pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, beginErr := m.beginWith(ctx, txOptions)
+	if beginErr != nil {
+		return fmt.Errorf("begin transaction failed: %w", beginErr)
+	}
+	if err := fn(tx); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return errors.Join(fmt.Errorf("rollback failed: %w", rollbackErr), err)
+		}
+		return err
+	}
+	if commitErr := tx.Commit(ctx); commitErr != nil {
+		return fmt.Errorf("commit transaction failed: %w", commitErr)
+	}
+	return nil
+}
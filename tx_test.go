@@ -0,0 +1,24 @@
+package vermig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestEnsureTableIgnoresTxOptions(t *testing.T) {
+	db := new(recordingDB)
+	m := newVermig(WithDB(db), WithTxOptions(pgx.TxOptions{AccessMode: pgx.ReadOnly}))
+	if _, err := m.begin(t.Context()); !errors.Is(err, ErrTxOptionsUnsupported) {
+		t.Fatalf("begin: got %v, want %v", err, ErrTxOptionsUnsupported)
+	}
+	if err := m.ensureTable(t.Context()); errors.Is(err, ErrTxOptionsUnsupported) {
+		t.Errorf("ensure table used the transaction options: %s", err)
+	}
+}
+
+func TestNewReadOnlyTxOptions(t *testing.T) {
+	db := testDB(t)
+	testVermig(t, db, testFiles("1.0.0"), WithTxOptions(pgx.TxOptions{AccessMode: pgx.ReadOnly}))
+}
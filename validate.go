@@ -0,0 +1,92 @@
+package vermig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) Validate(ctx context.Context, version string) error {
+	ctx = m.withCorrelationID(ctx)
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	pv, resolveVersionErr := m.resolveTargetVersion(version)
+	if resolveVersionErr != nil {
+		return fmt.Errorf("parse version failed: %w", resolveVersionErr)
+	}
+	if m.pinnedVersion != nil && pv.GreaterThan(m.pinnedVersion) {
+		return fmt.Errorf("%w: %s is above %s", ErrVersionPinned, pv, m.pinnedVersion)
+	}
+	tx, beginErr := m.begin(ctx)
+	if beginErr != nil {
+		return fmt.Errorf("begin validation failed: %w", beginErr)
+	}
+	validateErr := m.validate(ctx, tx, pv)
+	if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+		return errors.Join(fmt.Errorf("rollback validation failed: %w", rollbackErr), validateErr)
+	}
+	return validateErr
+}
+
+func (m *Vermig) validate(ctx context.Context, tx pgx.Tx, targetVersion *semver.Version) error {
+	if err := m.setSearchPath(ctx, tx); err != nil {
+		return fmt.Errorf("set search path failed: %w", err)
+	}
+	if err := m.runPreamble(ctx, tx); err != nil {
+		return fmt.Errorf("run preamble failed: %w", err)
+	}
+	p, buildPlanErr := m.buildPlan(ctx, tx, targetVersion, nil)
+	if buildPlanErr != nil {
+		return fmt.Errorf("build plan failed: %w", buildPlanErr)
+	}
+	var errs []error
+	for _, file := range p.up {
+		query, transformErr := m.transformSQL(file, file.Up)
+		if transformErr != nil {
+			errs = append(errs, file.migrationError(transformErr))
+			continue
+		}
+		savepoint, beginErr := tx.Begin(ctx)
+		if beginErr != nil {
+			return fmt.Errorf("begin savepoint failed: %w", beginErr)
+		}
+		if execErr := execAsRole(
+			ctx, savepoint, file.Directives.Get(roleDirective), func() error {
+				return validateQuery(ctx, savepoint, query)
+			},
+		); execErr != nil {
+			if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+				return errors.Join(fmt.Errorf("rollback savepoint failed: %w", rollbackErr), file.migrationError(execErr))
+			}
+			m.logf(ctx, "🔎 %s/%s: ❌ %v", file.Scope, file.Name, execErr)
+			errs = append(errs, file.migrationError(execErr))
+			continue
+		}
+		if commitErr := savepoint.Commit(ctx); commitErr != nil {
+			return fmt.Errorf("release savepoint failed: %w", commitErr)
+		}
+		m.logf(ctx, "🔎 %s/%s: ✅", file.Scope, file.Name)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("validate migrations failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validateQuery(ctx context.Context, tx pgx.Tx, query string) error {
+	if !usesNamedArgs(ctx, query) {
+		_, err := tx.Exec(ctx, query)
+		return err
+	}
+	for _, statement := range splitStatements(query) {
+		if err := execStatement(ctx, tx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
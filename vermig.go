@@ -2,14 +2,15 @@ package vermig
 
 import (
 	"context"
-	"embed"
 	"errors"
 	"fmt"
-	"io/fs"
+	"io"
 	"log"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/Masterminds/squirrel"
@@ -18,233 +19,679 @@ import (
 )
 
 type Vermig struct {
-	db             DB
-	fs             embed.FS
-	allowDowngrade bool
-	files          []File
+	db                    DB
+	sources               []Source
+	allowDowngrade        bool
+	strictOrphans         bool
+	withoutAutoCreate     bool
+	extraColumns          map[string]func(File) any
+	idempotentInsert      bool
+	txOptions             pgx.TxOptions
+	rootScope             string
+	eventHandler          func(Event)
+	statementSplitting    bool
+	maxVersion            string
+	pinnedVersion         *semver.Version
+	skipPredicate         func(File) bool
+	serialID              bool
+	idType                string
+	afterCommit           func(ctx context.Context, applied, reverted []Migration) error
+	logger                *log.Logger
+	correlationID         string
+	versionParser         func(filename string) (string, error)
+	schema                string
+	checksumNormalization ChecksumNormalization
+	blockDestructiveDown  bool
+	batchSize             int
+	nameColumnLength      int
+	managedSchema         string
+	devMode               bool
+	repeatables           []File
+	statementBuilder      squirrel.StatementBuilderType
+	reportWriter          io.Writer
+	applicationName       string
+	deferredRecording     bool
+	preamble              string
+	seeds                 []func(ctx context.Context, tx pgx.Tx) error
+	freshTable            *atomic.Bool
+	downCheck             DownCheck
+	versionTable          bool
+	withoutDownSQL        bool
+	layout                Layout
+	stopSignal            <-chan struct{}
+	columnMapping         map[string]string
+	echoSQLEnabled        bool
+	echoSQLLimit          int
+	ensureTableOnMigrate  bool
+	overlays              []Source
+	planApprover          func(plan []PlannedOp) error
+	allowedWindow         func(now time.Time) bool
+	ordering              OrderingMode
+	running               *atomic.Bool
+	sqlTransform          func(file File, sql string) (string, error)
+	files                 []File
 }
 
 func New(ctx context.Context, options ...Option) (*Vermig, error) {
-	m := new(Vermig)
-	for _, option := range options {
-		option(m)
+	m := newVermig(options...)
+	if m.maxVersion != "" {
+		pinnedVersion, parsePinnedVersionErr := parseVersion(m.maxVersion)
+		if parsePinnedVersionErr != nil {
+			return nil, fmt.Errorf("parse max version failed: %w", parsePinnedVersionErr)
+		}
+		m.pinnedVersion = pinnedVersion
 	}
-	migrationsTableExists, getMigrationsTableExistErr := m.migrationsTableExists(ctx)
-	if getMigrationsTableExistErr != nil {
-		return nil, fmt.Errorf("get migrations table exitence failed: %w", getMigrationsTableExistErr)
+	if err := m.checkColumnMapping(); err != nil {
+		return nil, err
 	}
-	if migrationsTableExists {
-		return m, nil
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
 	}
-	if err := m.createTableIfNotExists(ctx); err != nil {
-		return nil, fmt.Errorf("create migrations table failed: %w", err)
+	if err := m.checkTableColumns(ctx); err != nil {
+		return nil, fmt.Errorf("check migrations table failed: %w", err)
 	}
 	return m, nil
 }
 
-func (m *Vermig) MigrateLatest(ctx context.Context) error {
-	candidates := make([]*semver.Version, 0)
-	if err := fs.WalkDir(
-		m.fs, ".", func(path string, entry fs.DirEntry, err error) error {
-			if path == "" || entry.IsDir() {
-				return nil
+func newVermig(options ...Option) *Vermig {
+	m := &Vermig{
+		statementBuilder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		running:          new(atomic.Bool),
+		freshTable:       new(atomic.Bool),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+func (m *Vermig) ensureTable(ctx context.Context) error {
+	if m.withoutAutoCreate {
+		migrationsTableExists, getMigrationsTableExistErr := m.migrationsTableExists(ctx, m.db)
+		if getMigrationsTableExistErr != nil {
+			return fmt.Errorf("get migrations table exitence failed: %w", getMigrationsTableExistErr)
+		}
+		if !migrationsTableExists {
+			return ErrMigrationsTableMissing
+		}
+		return nil
+	}
+	return m.inTxWith(
+		ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "vermig:"+m.table()); err != nil {
+				return fmt.Errorf("lock migrations table setup failed: %w", err)
 			}
-			name := entry.Name()
-			if strings.HasSuffix(name, "_down.sql") {
-				return nil
+			if m.schema != "" || m.managedSchema != "" {
+				if err := m.createSchema(ctx, tx); err != nil {
+					return fmt.Errorf("create migrations schema failed: %w", err)
+				}
+			}
+			migrationsTableExists, getMigrationsTableExistErr := m.migrationsTableExists(ctx, tx)
+			if getMigrationsTableExistErr != nil {
+				return fmt.Errorf("get migrations table exitence failed: %w", getMigrationsTableExistErr)
+			}
+			if !migrationsTableExists {
+				if err := m.createTableIfNotExists(ctx, tx); err != nil {
+					return fmt.Errorf("create migrations table failed: %w", err)
+				}
+				m.freshTable.Store(true)
 			}
-			rawVersion := name[:strings.Index(name, "_")]
-			version, parseVersionErr := semver.NewVersion(rawVersion)
-			if parseVersionErr != nil {
-				return fmt.Errorf("parse version failed: %w", parseVersionErr)
+			if err := m.upgradeTable(ctx, tx); err != nil {
+				return fmt.Errorf("upgrade migrations table failed: %w", err)
+			}
+			if err := m.readIDType(ctx, tx); err != nil {
+				return err
+			}
+			if err := m.adoptLegacyRootScopes(ctx, tx); err != nil {
+				return fmt.Errorf("adopt legacy root scopes failed: %w", err)
+			}
+			if m.versionTable {
+				if err := m.createVersionTable(ctx, tx); err != nil {
+					return fmt.Errorf("create version table failed: %w", err)
+				}
+				if err := m.syncVersionTable(ctx, tx); err != nil {
+					return fmt.Errorf("sync version table failed: %w", err)
+				}
 			}
-			candidates = append(candidates, version)
 			return nil
 		},
-	); err != nil {
-		return fmt.Errorf("find latest migration failed: %w", err)
+	)
+}
+
+func (m *Vermig) MigrateLatest(ctx context.Context) error {
+	if err := m.enter(); err != nil {
+		return err
 	}
-	if len(candidates) == 0 {
-		return fmt.Errorf("no migrations found")
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
 	}
-	sort.Sort(semver.Collection(candidates))
-	return m.Migrate(ctx, candidates[len(candidates)-1].String())
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("find latest migration failed: %w", collectFilesErr)
+	}
+	if len(m.files) == 0 {
+		return ErrNoMigrationsFound
+	}
+	return m.migrateTo(ctx, m.latestVersion().String(), nil)
+}
+
+func (m *Vermig) stopRequested() bool {
+	select {
+	case <-m.stopSignal:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Vermig) latestVersion() *semver.Version {
+	latest := m.files[0].Version
+	for _, file := range m.files {
+		if file.Version.GreaterThan(latest) {
+			latest = file.Version
+		}
+	}
+	if m.pinnedVersion != nil && latest.GreaterThan(m.pinnedVersion) {
+		latest = m.pinnedVersion
+	}
+	return latest
 }
 
 func (m *Vermig) Migrate(ctx context.Context, version string) error {
-	pv, parseVersionErr := semver.NewVersion(version)
-	if parseVersionErr != nil {
-		return fmt.Errorf("parse version failed: %w", parseVersionErr)
+	if err := m.enter(); err != nil {
+		return err
 	}
-	higherMigrations, findMigrationsErr := m.findHigherVersionMigrations(
-		ctx, m.db, pv,
-	)
-	if findMigrationsErr != nil {
-		return fmt.Errorf("find higher version migrations failed: %w", findMigrationsErr)
+	defer m.leave()
+	ctx = m.withCorrelationID(ctx)
+	if err := m.checkWindow(ctx); err != nil {
+		return err
+	}
+	return m.migrateTo(ctx, version, nil)
+}
+
+func (m *Vermig) migrateTo(ctx context.Context, version string, include func(File) bool) error {
+	ctx = m.withCorrelationID(ctx)
+	if m.ensureTableOnMigrate {
+		if err := m.reensureTable(ctx); err != nil {
+			return err
+		}
+	}
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	pv, resolveVersionErr := m.resolveTargetVersion(version)
+	if resolveVersionErr != nil {
+		return fmt.Errorf("parse version failed: %w", resolveVersionErr)
+	}
+	if m.pinnedVersion != nil && pv.GreaterThan(m.pinnedVersion) {
+		return fmt.Errorf("%w: %s is above %s", ErrVersionPinned, pv, m.pinnedVersion)
+	}
+	if len(m.files) == 0 {
+		return ErrNoMigrationsFound
+	}
+	return m.migrate(ctx, pv, include)
+}
+
+func (m *Vermig) migrateBatches(ctx context.Context, targetVersion *semver.Version, include func(File) bool) error {
+	p, upToDate, upToDateErr := m.upToDate(ctx, targetVersion, include)
+	if upToDateErr != nil {
+		return upToDateErr
+	}
+	if upToDate {
+		m.logf(ctx, "migrator status: already up to date ✅")
+		return nil
+	}
+	approved, approvePlanErr := m.approvePlan(p)
+	if approvePlanErr != nil {
+		return fmt.Errorf("approve plan failed: %w", approvePlanErr)
 	}
-	tx, beginErr := m.db.Begin(ctx)
+	var postErrs []error
+	for {
+		if m.stopRequested() {
+			current, currentVersionErr := m.CurrentVersion(ctx)
+			if currentVersionErr != nil {
+				return errors.Join(
+					append(postErrs, ErrStopped, fmt.Errorf("get current version failed: %w", currentVersionErr))...,
+				)
+			}
+			m.logf(ctx, "⏹️ migrator stopped at %s", current)
+			return errors.Join(append(postErrs, fmt.Errorf("%w: reached %s, target %s", ErrStopped, current, targetVersion))...)
+		}
+		more, err := m.migrateBatch(ctx, targetVersion, include, &approved)
+		if errors.Is(err, ErrPostStepFailed) && !errors.Is(err, errAfterCommitFailed) {
+			postErrs = append(postErrs, err)
+		} else if err != nil {
+			return errors.Join(append(postErrs, err)...)
+		}
+		if !more {
+			return errors.Join(postErrs...)
+		}
+	}
+}
+
+func (m *Vermig) migrateBatch(
+	ctx context.Context, targetVersion *semver.Version, include func(File) bool, approved *[]PlannedOp,
+) (bool, error) {
+	tx, beginErr := m.begin(ctx)
 	if beginErr != nil {
-		return fmt.Errorf("begin migrations failed: %w", beginErr)
+		return false, fmt.Errorf("begin migrations failed: %w", beginErr)
+	}
+	if err := m.setSearchPath(ctx, tx); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while set search path failed: %w", rollbackErr),
+				fmt.Errorf("set search path failed: %w", err),
+			)
+		}
+		return false, fmt.Errorf("set search path failed: %w", err)
 	}
-	if err := m.collectFiles(); err != nil {
-		return fmt.Errorf("collect migrations failed: %w", err)
+	if err := m.runPreamble(ctx, tx); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while run preamble failed: %w", rollbackErr),
+				fmt.Errorf("run preamble failed: %w", err),
+			)
+		}
+		return false, fmt.Errorf("run preamble failed: %w", err)
+	}
+	if err := m.lockTable(ctx, tx); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while lock migrations table failed: %w", rollbackErr),
+				fmt.Errorf("lock migrations table failed: %w", err),
+			)
+		}
+		return false, fmt.Errorf("lock migrations table failed: %w", err)
 	}
 	if err := m.verifyIntegrity(ctx, tx); err != nil {
+		m.emitRollback(ctx, err)
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			return errors.Join(
+			return false, errors.Join(
 				fmt.Errorf("rollback while verify integrity failed: %w", rollbackErr),
 				fmt.Errorf("verify integrity failed: %w", err),
 			)
 		}
-		return fmt.Errorf("verify integrity failed: %w", err)
+		return false, fmt.Errorf("verify integrity failed: %w", err)
+	}
+	p, buildPlanErr := m.buildPlan(ctx, tx, targetVersion, include)
+	if buildPlanErr != nil {
+		m.emitRollback(ctx, buildPlanErr)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while build plan failed: %w", rollbackErr),
+				fmt.Errorf("build plan failed: %w", buildPlanErr),
+			)
+		}
+		return false, fmt.Errorf("build plan failed: %w", buildPlanErr)
+	}
+	if err := m.checkApproved(p, *approved); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while check approved plan failed: %w", rollbackErr),
+				fmt.Errorf("check approved plan failed: %w", err),
+			)
+		}
+		return false, fmt.Errorf("check approved plan failed: %w", err)
+	}
+	more := m.limitBatch(&p)
+	if m.planApprover != nil {
+		*approved = (*approved)[len(p.down)+len(p.up):]
+	}
+	if p.downgradeBlocked {
+		m.logf(ctx, "⚠️ downgrade not enabled")
 	}
-	if !m.allowDowngrade && higherMigrations != nil && len(higherMigrations) > 0 {
-		log.Printf("⚠️ downgrade not enabled\n")
+	for _, file := range p.skipped {
+		m.logf(ctx, "⏭️ %s/%s: skipped", file.Scope, file.Name)
 	}
-	if m.allowDowngrade && higherMigrations != nil && len(higherMigrations) > 0 {
-		if migrateDownErr := m.migrateDown(ctx, tx, higherMigrations); migrateDownErr != nil {
+	if len(p.down) > 0 {
+		if migrateDownErr := m.migrateDown(ctx, tx, p.down); migrateDownErr != nil {
+			m.emitRollback(ctx, migrateDownErr)
 			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-				return errors.Join(
+				return false, errors.Join(
 					fmt.Errorf("rollback while downgrade db failed: %w", rollbackErr),
 					fmt.Errorf("downgrade db failed: %w", migrateDownErr),
 				)
 			}
-			return fmt.Errorf("downgrade db failed: %w", migrateDownErr)
+			return false, fmt.Errorf("downgrade db failed: %w", migrateDownErr)
 		}
 	}
-	if higherMigrations == nil || len(higherMigrations) == 0 {
-		if migrateUpErr := m.migrateUp(
-			ctx, tx, pv,
-		); migrateUpErr != nil {
+	var applied []Migration
+	if len(p.up) > 0 {
+		var migrateUpErr error
+		if applied, migrateUpErr = m.migrateUp(ctx, tx, p.up); migrateUpErr != nil {
+			m.emitRollback(ctx, migrateUpErr)
 			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-				return errors.Join(
+				return false, errors.Join(
 					fmt.Errorf("rollback while upgrade db failed: %w", rollbackErr),
 					fmt.Errorf("upgrade db failed: %w", migrateUpErr),
 				)
 			}
-			return fmt.Errorf("upgrade db failed: %w", migrateUpErr)
+			return false, fmt.Errorf("upgrade db failed: %w", migrateUpErr)
+		}
+	}
+	if !more && len(m.repeatables) > 0 {
+		repeated, migrateRepeatablesErr := m.migrateRepeatables(ctx, tx)
+		if migrateRepeatablesErr != nil {
+			m.emitRollback(ctx, migrateRepeatablesErr)
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return false, errors.Join(
+					fmt.Errorf("rollback while apply repeatable migrations failed: %w", rollbackErr),
+					fmt.Errorf("apply repeatable migrations failed: %w", migrateRepeatablesErr),
+				)
+			}
+			return false, fmt.Errorf("apply repeatable migrations failed: %w", migrateRepeatablesErr)
+		}
+		applied = append(applied, repeated...)
+	}
+	if !more && m.freshTable.Load() {
+		if err := m.runSeeds(ctx, tx); err != nil {
+			m.emitRollback(ctx, err)
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return false, errors.Join(
+					fmt.Errorf("rollback while run seeds failed: %w", rollbackErr),
+					fmt.Errorf("run seeds failed: %w", err),
+				)
+			}
+			return false, fmt.Errorf("run seeds failed: %w", err)
+		}
+	}
+	if err := m.syncVersionTable(ctx, tx); err != nil {
+		m.emitRollback(ctx, err)
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return false, errors.Join(
+				fmt.Errorf("rollback while sync version table failed: %w", rollbackErr),
+				fmt.Errorf("sync version table failed: %w", err),
+			)
 		}
+		return false, fmt.Errorf("sync version table failed: %w", err)
 	}
 	if commitErr := tx.Commit(ctx); commitErr != nil {
+		m.emitRollback(ctx, commitErr)
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			return errors.Join(
+			return false, errors.Join(
 				fmt.Errorf("rollback while commit migrations failed: %w", rollbackErr),
 				fmt.Errorf("commit migrations failed: %w", commitErr),
 			)
 		}
-		return fmt.Errorf("commit migrations failed: %w", commitErr)
+		return false, fmt.Errorf("commit migrations failed: %w", commitErr)
+	}
+	commitReport(ctx)
+	recordResult(ctx, applied, p.down)
+	if !more {
+		m.freshTable.Store(false)
+	}
+	m.logf(ctx, "migrator status: ✅")
+	var afterCommitErr error
+	if m.afterCommit != nil {
+		if err := m.afterCommit(ctx, applied, p.down); err != nil {
+			afterCommitErr = fmt.Errorf("%w: %w", errAfterCommitFailed, err)
+		}
 	}
-	log.Println("migrator status: ✅")
-	return nil
+	if err := m.runPostSteps(ctx, p.up); err != nil {
+		return more, errors.Join(afterCommitErr, fmt.Errorf("run post steps failed: %w", err))
+	}
+	return more, afterCommitErr
 }
 
-func (m *Vermig) migrateUp(
-	ctx context.Context, tx pgx.Tx,
-	targetVersion *semver.Version,
-) error {
-	for _, file := range m.files {
-		if file.Version.GreaterThan(targetVersion) {
-			continue
+func (m *Vermig) migrateUp(ctx context.Context, tx pgx.Tx, files []File) ([]Migration, error) {
+	applied := make([]Migration, 0, len(files))
+	var extras []map[string]any
+	for _, file := range files {
+		var restoreLockTimeout func() error
+		if lockTimeout := file.Directives.Get("lock-timeout"); lockTimeout != "" {
+			timeout, parseTimeoutErr := time.ParseDuration(lockTimeout)
+			if parseTimeoutErr != nil {
+				return nil, &ParseError{Path: file.UpPath, Err: fmt.Errorf("parse lock timeout failed: %w", parseTimeoutErr)}
+			}
+			restore, setLockTimeoutErr := setLocal(ctx, tx, "lock_timeout", fmt.Sprintf("%dms", timeout.Milliseconds()))
+			if setLockTimeoutErr != nil {
+				return nil, fmt.Errorf("set lock timeout failed: %w", setLockTimeoutErr)
+			}
+			restoreLockTimeout = restore
 		}
-		migrationExists, existsErr := m.migrationExists(ctx, tx, file.Name, file.Scope)
-		if existsErr != nil {
-			return fmt.Errorf("verify migration existence failed: %w", existsErr)
+		if err := m.checkNameLength(file); err != nil {
+			return nil, err
 		}
-		if migrationExists {
-			continue
+		if file.Directives.Has("preamble") {
+			if err := m.runPreamble(ctx, tx); err != nil {
+				return nil, fmt.Errorf("run preamble failed: %w", file.migrationError(err))
+			}
+		}
+		startedAt := time.Now()
+		event := Event{
+			Direction: DirectionUp,
+			Scope:     file.Scope,
+			Name:      file.Name,
+			Version:   file.Version.String(),
+		}
+		query, transformErr := m.transformSQL(file, file.Up)
+		if transformErr != nil {
+			return nil, file.migrationError(transformErr)
+		}
+		m.echoSQL(ctx, DirectionUp, file.Scope, file.Name, query)
+		exec := m.execQuery
+		if file.Directives.Has(ifNotExistsOkDirective) {
+			exec = m.execTolerant
 		}
-		fileBytes, readMigrationUp := m.fs.ReadFile(file.UpPath)
-		if readMigrationUp != nil {
-			return fmt.Errorf("read migration file failed: %w", readMigrationUp)
-		}
-		var queryDown string
-		if downFileBytes, readMigrationDown := m.fs.ReadFile(file.DownPath); readMigrationDown == nil {
-			queryDown = string(downFileBytes)
-		}
-		queryUp := string(fileBytes)
-		if _, execErr := tx.Exec(ctx, queryUp); execErr != nil {
-			return fmt.Errorf("run migration up failed: %w", execErr)
-		}
-		log.Printf("🔼 %s/%s: ✅\n", file.Scope, file.Name)
-		if insertMigrationErr := m.insertMigration(
-			ctx, tx, Migration{
-				Name:       file.Name,
-				Version:    file.Version.String(),
-				Major:      file.Version.Major(),
-				Minor:      file.Version.Minor(),
-				Patch:      file.Version.Patch(),
-				Prerelease: file.Version.Prerelease(),
-				Scope:      file.Scope,
-				Up:         queryUp,
-				Down:       queryDown,
-				Checksum:   createChecksum(queryUp, queryDown),
+		if execErr := execAsRole(
+			ctx, tx, file.Directives.Get(roleDirective), func() error {
+				return exec(ctx, tx, event, query)
 			},
-		); insertMigrationErr != nil {
-			return fmt.Errorf("insert migration failed: %w", insertMigrationErr)
+		); execErr != nil {
+			return nil, fmt.Errorf("run migration up failed: %w", file.migrationError(execErr))
+		}
+		if restoreLockTimeout != nil {
+			if err := restoreLockTimeout(); err != nil {
+				return nil, fmt.Errorf("reset lock timeout failed: %w", err)
+			}
+		}
+		m.logf(ctx, "🔼 %s/%s: ✅", file.Scope, file.Name)
+		migration := Migration{
+			Name:       file.Name,
+			Version:    file.Version.String(),
+			Major:      file.Version.Major(),
+			Minor:      file.Version.Minor(),
+			Patch:      file.Version.Patch(),
+			Prerelease: file.Version.Prerelease(),
+			Step:       file.Step,
+			Scope:      file.Scope,
+			Up:         file.Up,
+			Down:       m.storedDownSQL(file),
+			Checksum:   fileChecksum(file, m.checksumNormalization),
+			SourcePath: file.UpPath,
+			Tags:       strings.Join(file.Tags(), ","),
+		}
+		if m.deferredRecording {
+			extras = append(extras, m.extraValues(file))
+		} else if insertMigrationErr := m.insertMigration(ctx, tx, migration, m.extraValues(file)); insertMigrationErr != nil {
+			return nil, fmt.Errorf("insert migration failed: %w", file.migrationError(insertMigrationErr))
+		}
+		applied = append(applied, migration)
+		recordReport(ctx, DirectionUp, file.Scope, file.Name, migration.Version, startedAt)
+		event.Type = EventMigrationApplied
+		m.emit(ctx, event)
+	}
+	if m.deferredRecording {
+		if insertMigrationsErr := m.insertMigrations(ctx, tx, applied, extras); insertMigrationsErr != nil {
+			return nil, fmt.Errorf("insert migrations failed: %w", insertMigrationsErr)
 		}
 	}
-	return nil
+	return applied, nil
 }
 
 func (m *Vermig) migrateDown(ctx context.Context, tx pgx.Tx, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	if m.withoutDownSQL {
+		return fmt.Errorf("%w: downgrades need WithStoreDownSQL(true)", ErrDownSQLNotStored)
+	}
+	if err := m.checkDestructiveDown(ctx, migrations); err != nil {
+		return err
+	}
+	if err := m.checkDownConsistency(ctx, migrations); err != nil {
+		return err
+	}
 	ids := make([]string, len(migrations))
 	for i, migration := range migrations {
-		if _, execErr := tx.Exec(ctx, migration.Down); execErr != nil {
-			return fmt.Errorf("run migration down failed: %w", execErr)
+		startedAt := time.Now()
+		event := Event{
+			Direction: DirectionDown,
+			Scope:     migration.Scope,
+			Name:      migration.Name,
+			Version:   migration.Version,
+		}
+		query, transformErr := m.transformSQL(migration.file(), migration.Down)
+		if transformErr != nil {
+			return migration.migrationError(transformErr)
 		}
-		log.Printf("🔽 %s/%s: ✅\n", migration.Scope, migration.Name)
+		m.echoSQL(ctx, DirectionDown, migration.Scope, migration.Name, query)
+		if execErr := execAsRole(
+			ctx, tx, parseDirectives(migration.Down).Get(roleDirective), func() error {
+				return m.execQuery(ctx, tx, event, query)
+			},
+		); execErr != nil {
+			return fmt.Errorf("run migration down failed: %w", migration.migrationError(execErr))
+		}
+		m.logf(ctx, "🔽 %s/%s: ✅", migration.Scope, migration.Name)
+		recordReport(ctx, DirectionDown, migration.Scope, migration.Name, migration.Version, startedAt)
 		ids[i] = migration.Id
 	}
 	if deleteMigrationsErr := m.deleteMigrations(ctx, tx, ids...); deleteMigrationsErr != nil {
 		return fmt.Errorf("delete migrations failed: %w", deleteMigrationsErr)
 	}
+	for _, migration := range migrations {
+		m.emit(
+			ctx,
+			Event{
+				Type:      EventMigrationReverted,
+				Direction: DirectionDown,
+				Scope:     migration.Scope,
+				Name:      migration.Name,
+				Version:   migration.Version,
+			},
+		)
+	}
+	return nil
+}
+
+func (m *Vermig) reensureTable(ctx context.Context) error {
+	exists, existsErr := m.migrationsTableExists(ctx, m.db)
+	if existsErr != nil {
+		return fmt.Errorf("get migrations table existence failed: %w", existsErr)
+	}
+	if exists {
+		return nil
+	}
+	m.logf(ctx, "⚠️ migrations table missing, recreating")
+	if err := m.ensureTable(ctx); err != nil {
+		return fmt.Errorf("recreate migrations table failed: %w", err)
+	}
 	return nil
 }
 
-func (m *Vermig) migrationsTableExists(ctx context.Context) (bool, error) {
+func (m *Vermig) migrationsTableExists(ctx context.Context, db DB) (bool, error) {
 	query := `SELECT EXISTS (
     SELECT FROM
         pg_tables
     WHERE
-        schemaname = 'public' AND
+        schemaname = $1 AND
         tablename  = 'migrations'
     );`
 	var exists bool
-	if err := pgxscan.Get(ctx, m.db, &exists, query); err != nil {
+	if err := pgxscan.Get(ctx, db, &exists, query, m.tableSchema()); err != nil {
 		return false, fmt.Errorf("check migrations table existence failed: %w", err)
 	}
 	return exists, nil
 }
 
-func (m *Vermig) createTableIfNotExists(ctx context.Context) error {
-	query := `CREATE TABLE IF NOT EXISTS migrations (
-  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-	name VARCHAR(255) NOT NULL,
-	version VARCHAR(64) NOT NULL,
-	major INT NOT NULL,
-	minor INT NOT NULL,
-	patch INT NOT NULL,
-	prerelease VARCHAR(128) NOT NULL,
-	scope VARCHAR(255) NOT NULL,
-	up TEXT NOT NULL,
-	down TEXT NOT NULL,
-	checksum TEXT NOT NULL,
-	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-	CONSTRAINT uq_scope_version UNIQUE (scope, version, major, minor, patch, prerelease)
+func (m *Vermig) createTableIfNotExists(ctx context.Context, db DB) error {
+	c := m.col
+	idColumn := c("id") + " UUID PRIMARY KEY DEFAULT gen_random_uuid()"
+	if m.serialID {
+		idColumn = c("id") + " BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY"
+	}
+	table := m.table()
+	nameColumnLength := strconv.Itoa(m.columnLength())
+	query := `CREATE TABLE IF NOT EXISTS ` + table + ` (
+  ` + idColumn + `,
+	` + c("name") + ` VARCHAR(` + nameColumnLength + `) NOT NULL,
+	` + c("version") + ` VARCHAR(64),
+	` + c("major") + ` INT NOT NULL,
+	` + c("minor") + ` INT NOT NULL,
+	` + c("patch") + ` INT NOT NULL,
+	` + c("prerelease") + ` VARCHAR(128) NOT NULL,
+	` + c("step") + ` INT NOT NULL DEFAULT 0,
+	` + c("scope") + ` VARCHAR(` + nameColumnLength + `) NOT NULL,
+	` + c("up") + ` TEXT NOT NULL,
+	` + c("down") + ` TEXT NOT NULL,
+	` + c("checksum") + ` TEXT NOT NULL,
+	` + c("source_path") + ` TEXT NOT NULL DEFAULT '',
+	` + c("tags") + ` TEXT NOT NULL DEFAULT '',
+	` + c("created_at") + ` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT uq_scope_version UNIQUE (` + m.uniqueColumns() + `)
 );
-CREATE INDEX IF NOT EXISTS idx_migrations_name ON migrations (name);
-CREATE INDEX IF NOT EXISTS idx_migrations_version ON migrations (version);
-CREATE INDEX IF NOT EXISTS idx_migrations_major_version ON migrations (major);
-CREATE INDEX IF NOT EXISTS idx_migrations_minor_version ON migrations (minor);
-CREATE INDEX IF NOT EXISTS idx_migrations_patch_version ON migrations (patch);
-CREATE INDEX IF NOT EXISTS idx_migrations_prerelease ON migrations (prerelease);
-CREATE INDEX IF NOT EXISTS idx_migrations_scope ON migrations (scope);`
-	if _, err := m.db.Exec(ctx, query); err != nil {
+CREATE INDEX IF NOT EXISTS idx_migrations_name ON ` + table + ` (` + c("name") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_version ON ` + table + ` (` + c("version") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_major_version ON ` + table + ` (` + c("major") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_minor_version ON ` + table + ` (` + c("minor") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_patch_version ON ` + table + ` (` + c("patch") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_prerelease ON ` + table + ` (` + c("prerelease") + `);
+CREATE INDEX IF NOT EXISTS idx_migrations_scope ON ` + table + ` (` + c("scope") + `);`
+	if _, err := db.Exec(ctx, query); err != nil {
 		return fmt.Errorf("create migrations table failed: %w", err)
 	}
 	return nil
 }
 
+func (m *Vermig) upgradeTable(ctx context.Context, db DB) error {
+	table, c := m.table(), m.col
+	query := `ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS ` + c("source_path") + ` TEXT NOT NULL DEFAULT '';
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS ` + c("step") + ` INT NOT NULL DEFAULT 0;
+ALTER TABLE ` + table + ` ALTER COLUMN ` + c("version") + ` DROP NOT NULL;
+ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS ` + c("tags") + ` TEXT NOT NULL DEFAULT '';`
+	if m.nameColumnLength > 0 {
+		query += fmt.Sprintf(
+			`
+DO $$
+BEGIN
+	IF (SELECT atttypmod - 4 FROM pg_attribute WHERE attrelid = '%[1]s'::regclass AND attname = '%[4]s') < %[2]d THEN
+		ALTER TABLE %[3]s ALTER COLUMN %[5]s TYPE VARCHAR(%[2]d);
+	END IF;
+	IF (SELECT atttypmod - 4 FROM pg_attribute WHERE attrelid = '%[1]s'::regclass AND attname = '%[6]s') < %[2]d THEN
+		ALTER TABLE %[3]s ALTER COLUMN %[7]s TYPE VARCHAR(%[2]d);
+	END IF;
+END $$;`, strings.ReplaceAll(table, "'", "''"), m.nameColumnLength, table,
+			strings.ReplaceAll(m.physicalColumn("name"), "'", "''"), c("name"),
+			strings.ReplaceAll(m.physicalColumn("scope"), "'", "''"), c("scope"),
+		)
+	}
+	for _, column := range m.extraColumnNames() {
+		query += "\nALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS " + pgx.Identifier{column}.Sanitize() + " TEXT;"
+	}
+	if _, err := db.Exec(ctx, query); err != nil {
+		return fmt.Errorf("upgrade migrations table failed: %w", err)
+	}
+	if err := m.ensureConstraints(ctx, db); err != nil {
+		return fmt.Errorf("ensure constraints failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Vermig) lockTable(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, "LOCK TABLE "+m.table()+" IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return fmt.Errorf("lock migrations table failed: %w", err)
+	}
+	return nil
+}
+
 func (m *Vermig) verifyIntegrity(
 	ctx context.Context, db DB,
 ) error {
@@ -257,68 +704,65 @@ func (m *Vermig) verifyIntegrity(
 	}
 	migrationChecksums := make(map[string]string, len(migrations))
 	for _, migration := range migrations {
-		migrationChecksums[migration.Scope+"/"+migration.Name] = migration.Checksum
+		migrationChecksums[migration.key()] = migration.Checksum
 	}
-	for _, file := range m.files {
-		var queryDown string
-		downFileBytes, readMigrationDownErr := m.fs.ReadFile(file.DownPath)
-		if readMigrationDownErr == nil {
-			queryDown = string(downFileBytes)
+	if m.strictOrphans {
+		files := make(map[string]struct{}, len(m.files))
+		for _, file := range m.files {
+			files[file.key()] = struct{}{}
 		}
-		if m.allowDowngrade && readMigrationDownErr != nil {
-			return fmt.Errorf("%s migration missing: %w", file.DownPath, readMigrationDownErr)
+		for _, migration := range migrations {
+			if _, exists := files[migration.key()]; !exists {
+				return fmt.Errorf("%w: %s/%s", ErrOrphanedMigration, migration.Scope, migration.Name)
+			}
 		}
-		fileBytes, readMigrationUp := m.fs.ReadFile(file.UpPath)
-		if readMigrationUp != nil {
-			return fmt.Errorf("read migration file failed: %w", readMigrationUp)
+	}
+	for _, file := range m.files {
+		if m.allowDowngrade && file.downErr != nil {
+			return fmt.Errorf("%s migration missing: %w", file.DownPath, file.downErr)
 		}
-		queryUp := string(fileBytes)
-		checksum := createChecksum(queryUp, queryDown)
-		storedChecksum, exists := migrationChecksums[file.Scope+"/"+file.Name]
+		storedChecksum, exists := migrationChecksums[file.key()]
 		if !exists {
 			continue
 		}
-		if checksum != storedChecksum {
+		if !checksumMatches(file, storedChecksum) {
 			return fmt.Errorf("corrupted migration: %s", file.Name)
 		}
 	}
 	return nil
 }
 
-func (m *Vermig) collectFiles() error {
-	m.files = m.files[:0]
-	if err := fs.WalkDir(
-		m.fs, ".", func(path string, entry fs.DirEntry, err error) error {
-			if path == "" || entry.IsDir() {
-				return nil
-			}
-			name := entry.Name()
-			if strings.HasSuffix(name, "_down.sql") {
-				return nil
-			}
-			downPath := strings.Replace(path, "_up.sql", "_down.sql", 1)
-			version := name[:strings.Index(name, "_")]
-			pv, parseVersionErr := semver.NewVersion(version)
-			if parseVersionErr != nil {
-				return fmt.Errorf("parse version failed: %w", parseVersionErr)
-			}
-			scope := strings.TrimSuffix(path, "/"+name)
-			m.files = append(
-				m.files, File{
-					Priority: m.parsePriority(path),
-					Version:  pv,
-					Scope:    scope,
-					Name:     name,
-					UpPath:   path,
-					DownPath: downPath,
-				},
-			)
-			return nil
-		},
-	); err != nil {
-		return fmt.Errorf("scan migrations failed: %w", err)
+func (m *Vermig) collectFiles() (*Vermig, error) {
+	collected := *m
+	collected.files, collected.repeatables = nil, nil
+	for _, source := range collected.sources {
+		if err := collected.collectSource(source); err != nil {
+			return nil, fmt.Errorf("scan migrations failed: %w", err)
+		}
+	}
+	if err := checkVersionCollisions(collected.files); err != nil {
+		return nil, err
+	}
+	if len(collected.overlays) > 0 {
+		if err := collected.collectOverlays(); err != nil {
+			return nil, err
+		}
+	}
+	collected.sortFiles()
+	if err := collected.orderDependencies(); err != nil {
+		return nil, fmt.Errorf("order dependencies failed: %w", err)
+	}
+	return &collected, nil
+}
+
+func checkVersionCollisions(files []File) error {
+	paths := make(map[string]string, len(files))
+	for _, file := range files {
+		if other, exists := paths[file.key()]; exists {
+			return fmt.Errorf("%w: %s and %s are both %s", ErrVersionCollision, other, file.UpPath, file.key())
+		}
+		paths[file.key()] = file.UpPath
 	}
-	m.sortFiles()
 	return nil
 }
 
@@ -334,9 +778,15 @@ func (m *Vermig) sortFiles() {
 			if len(pi) != len(pj) {
 				return len(pi) < len(pj)
 			}
+			if m.ordering == OrderingFilename {
+				return m.files[i].Name < m.files[j].Name
+			}
 			if !m.files[i].Version.Equal(m.files[j].Version) {
 				return m.files[i].Version.LessThan(m.files[j].Version)
 			}
+			if m.files[i].Step != m.files[j].Step {
+				return m.files[i].Step < m.files[j].Step
+			}
 			return m.files[i].Name < m.files[j].Name
 		},
 	)
@@ -356,65 +806,72 @@ func (m *Vermig) parsePriority(path string) []int {
 }
 
 func (m *Vermig) findAllMigrations(ctx context.Context, db DB) ([]Migration, error) {
-	sql, args, createSqlErr := squirrel.Select().
-		Columns(
-			"id", "name", "version", "major", "minor", "patch", "prerelease", "scope", "up",
-			"down", "checksum",
-		).
-		From("migrations").
+	sql, args, createSqlErr := m.statementBuilder.Select().
+		Columns(m.selectCols(migrationColumns...)...).
+		From(m.table()).
+		Where(m.col("version") + " IS NOT NULL").
 		ToSql()
 	if createSqlErr != nil {
 		return nil, fmt.Errorf("create higher version migrations sql failed: %w", createSqlErr)
 	}
 	rows, queryErr := db.Query(ctx, sql, args...)
 	if queryErr != nil {
-		return nil, fmt.Errorf("find higher version migrations failed: %w", queryErr)
+		return nil, fmt.Errorf("find higher version migrations failed: %w", m.diagnoseTableErr(ctx, db, queryErr))
 	}
 	defer rows.Close()
 	var result []Migration
 	if scanErr := pgxscan.ScanAll(&result, rows); scanErr != nil {
-		return nil, fmt.Errorf("scan higher version migrations failed: %w", scanErr)
+		return nil, fmt.Errorf("scan higher version migrations failed: %w", m.diagnoseTableErr(ctx, db, scanErr))
 	}
 	return result, nil
 }
 
 func (m *Vermig) findHigherVersionMigrations(
-	ctx context.Context, db DB, currentVersion *semver.Version,
+	ctx context.Context, db DB, currentVersion *semver.Version, columns []string,
 ) ([]Migration, error) {
-	sql, args, createSqlErr := squirrel.Select().
-		Columns(
-			"id", "name", "version", "major", "minor", "patch", "prerelease", "scope", "up",
-			"down", "checksum",
-		).
-		From("migrations").
+	sql, args, createSqlErr := m.statementBuilder.Select().
+		Columns(m.selectCols(columns...)...).
+		From(m.table()).
+		Where(m.col("version")+" IS NOT NULL").
 		Where(
-			"(major, minor, patch) > (?, ?, ?)", currentVersion.Major(), currentVersion.Minor(), currentVersion.Patch(),
+			"("+m.col("major")+", "+m.col("minor")+", "+m.col("patch")+") >= (?, ?, ?)",
+			currentVersion.Major(), currentVersion.Minor(), currentVersion.Patch(),
 		).
-		OrderBy("major DESC", "minor DESC", "patch DESC").
-		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if createSqlErr != nil {
 		return nil, fmt.Errorf("create higher version migrations sql failed: %w", createSqlErr)
 	}
 	rows, queryErr := db.Query(ctx, sql, args...)
 	if queryErr != nil {
-		return nil, fmt.Errorf("find higher version migrations failed: %w", queryErr)
+		return nil, fmt.Errorf("find higher version migrations failed: %w", m.diagnoseTableErr(ctx, db, queryErr))
 	}
 	defer rows.Close()
 	var result []Migration
 	if scanErr := pgxscan.ScanAll(&result, rows); scanErr != nil {
-		return nil, fmt.Errorf("scan higher version migrations failed: %w", scanErr)
+		return nil, fmt.Errorf("scan higher version migrations failed: %w", m.diagnoseTableErr(ctx, db, scanErr))
+	}
+	higherVersionMigrations, filterErr := filterHigherVersions(result, currentVersion)
+	if filterErr != nil {
+		return nil, filterErr
 	}
-	if len(result) == 0 {
-		return nil, nil
+	if err := m.sortDowngrades(higherVersionMigrations); err != nil {
+		return nil, fmt.Errorf("sort migrations failed: %w", err)
 	}
+	return higherVersionMigrations, nil
+}
+
+func filterHigherVersions(rows []Migration, currentVersion *semver.Version) ([]Migration, error) {
 	var higherVersionMigrations []Migration
-	for _, row := range result {
-		version, parseErr := semver.NewVersion(row.Version)
+	for _, row := range rows {
+		version, parseErr := parseVersion(row.Version)
 		if parseErr != nil {
 			return nil, fmt.Errorf("parse version failed: %w", parseErr)
 		}
-		if version == nil || !version.GreaterThan(currentVersion) {
+		if version == nil {
+			continue
+		}
+		if c := compareVersion(versionCore(version), versionCore(currentVersion)); c < 0 ||
+			(c == 0 && !version.GreaterThan(currentVersion)) {
 			continue
 		}
 		higherVersionMigrations = append(higherVersionMigrations, row)
@@ -422,12 +879,34 @@ func (m *Vermig) findHigherVersionMigrations(
 	return higherVersionMigrations, nil
 }
 
-func (m *Vermig) migrationExists(ctx context.Context, db DB, name, scope string) (bool, error) {
-	sql, args, createSqlErr := squirrel.Select().
+func (m *Vermig) findAppliedKeys(ctx context.Context, db DB) (map[string]struct{}, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select(m.selectCols("scope", "version", "step")...).
+		From(m.table()).
+		Where(m.col("version") + " IS NOT NULL").
+		ToSql()
+	if createSqlErr != nil {
+		return nil, fmt.Errorf("create applied migrations sql failed: %w", createSqlErr)
+	}
+	var rows []struct {
+		Scope   string
+		Version string
+		Step    int
+	}
+	if err := pgxscan.Select(ctx, db, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("select applied migrations failed: %w", m.diagnoseTableErr(ctx, db, err))
+	}
+	applied := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		applied[migrationKey(row.Scope, row.Version, row.Step)] = struct{}{}
+	}
+	return applied, nil
+}
+
+func (m *Vermig) migrationExists(ctx context.Context, db DB, scope, version string, step int) (bool, error) {
+	sql, args, createSqlErr := m.statementBuilder.Select().
 		Columns("true").
-		From("migrations").
-		Where(squirrel.Eq{"name": name, "scope": scope}).
-		PlaceholderFormat(squirrel.Dollar).
+		From(m.table()).
+		Where(squirrel.Eq{m.col("scope"): scope, m.col("version"): version, m.col("step"): step}).
 		ToSql()
 	if createSqlErr != nil && !errors.Is(createSqlErr, pgx.ErrNoRows) {
 		return false, fmt.Errorf("create migration exists sql failed: %w", createSqlErr)
@@ -439,17 +918,55 @@ func (m *Vermig) migrationExists(ctx context.Context, db DB, name, scope string)
 	return exists, nil
 }
 
-func (m *Vermig) insertMigration(ctx context.Context, db DB, migration Migration) error {
-	sql, args, createSqlErr := squirrel.Insert("migrations").
-		Columns(
-			"name", "version", "major", "minor", "patch", "prerelease", "scope", "up", "down", "checksum",
-		).
-		Values(
-			migration.Name, migration.Version, migration.Major, migration.Minor, migration.Patch, migration.Prerelease,
-			migration.Scope, migration.Up, migration.Down, migration.Checksum,
-		).
-		PlaceholderFormat(squirrel.Dollar).
-		ToSql()
+func (m *Vermig) insertMigration(ctx context.Context, db DB, migration Migration, extra map[string]any) error {
+	return m.insertMigrations(ctx, db, []Migration{migration}, []map[string]any{extra})
+}
+
+func (m *Vermig) insertMigrations(ctx context.Context, db DB, migrations []Migration, extras []map[string]any) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	var columns []string
+	for _, column := range []string{
+		"name", "version", "major", "minor", "patch", "prerelease", "step", "scope", "up", "down",
+		"checksum", "source_path", "tags",
+	} {
+		columns = append(columns, m.col(column))
+	}
+	withCreatedAt := true
+	for _, migration := range migrations {
+		withCreatedAt = withCreatedAt && !migration.CreatedAt.IsZero()
+	}
+	if withCreatedAt {
+		columns = append(columns, m.col("created_at"))
+	}
+	extraColumnNames := m.extraColumnNames()
+	for _, column := range extraColumnNames {
+		columns = append(columns, pgx.Identifier{column}.Sanitize())
+	}
+	builder := m.statementBuilder.Insert(m.table()).Columns(columns...)
+	for i, migration := range migrations {
+		var version any = migration.Version
+		if migration.Version == "" {
+			version = nil
+		}
+		values := []any{
+			migration.Name, version, migration.Major, migration.Minor, migration.Patch, migration.Prerelease,
+			migration.Step, migration.Scope, migration.Up, migration.Down, migration.Checksum, migration.SourcePath,
+			migration.Tags,
+		}
+		if withCreatedAt {
+			values = append(values, migration.CreatedAt)
+		}
+		for _, column := range extraColumnNames {
+			values = append(values, extras[i][column])
+		}
+		builder = builder.Values(values...)
+	}
+	if m.idempotentInsert {
+		builder = builder.Suffix("ON CONFLICT ON CONSTRAINT uq_scope_version DO NOTHING")
+	}
+	sql, args, createSqlErr := builder.ToSql()
 	if createSqlErr != nil {
 		return fmt.Errorf("create migration insert sql failed: %w", createSqlErr)
 	}
@@ -460,15 +977,24 @@ func (m *Vermig) insertMigration(ctx context.Context, db DB, migration Migration
 }
 
 func (m *Vermig) deleteMigrations(ctx context.Context, db DB, ids ...string) error {
-	sql, args, createSqlErr := squirrel.Delete("migrations").
-		Where(squirrel.Eq{"id": ids}).
-		PlaceholderFormat(squirrel.Dollar).
+	if len(ids) == 0 {
+		return nil
+	}
+	sql, args, createSqlErr := m.statementBuilder.Delete(m.table()).
+		Where(m.col("id")+" = ANY(?::text[]::"+m.idType+"[])", ids).
 		ToSql()
 	if createSqlErr != nil {
-		return fmt.Errorf("create migration insert sql failed: %w", createSqlErr)
+		return fmt.Errorf("create migrations delete sql failed: %w", createSqlErr)
 	}
 	if _, execErr := db.Exec(ctx, sql, args...); execErr != nil {
-		return fmt.Errorf("insert migration failed: %w", execErr)
+		return fmt.Errorf("delete migrations failed: %w", execErr)
 	}
 	return nil
 }
+
+func (m *Vermig) storedDownSQL(file File) string {
+	if m.withoutDownSQL {
+		return ""
+	}
+	return file.Down
+}
@@ -0,0 +1,190 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type recordingDB struct {
+	calls []string
+	args  [][]any
+}
+
+func (db *recordingDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	db.record(sql, args)
+	return nil, pgx.ErrNoRows
+}
+
+func (db *recordingDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	db.record(sql, args)
+	return nil
+}
+
+func (db *recordingDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	db.record(sql, args)
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *recordingDB) Begin(context.Context) (pgx.Tx, error) {
+	db.record("BEGIN", nil)
+	return nil, pgx.ErrTxClosed
+}
+
+func (db *recordingDB) record(sql string, args []any) {
+	db.calls = append(db.calls, sql)
+	db.args = append(db.args, args)
+}
+
+func TestDeleteMigrationsEmptyIDs(t *testing.T) {
+	db := new(recordingDB)
+	m := newVermig(WithDB(db))
+	if err := m.deleteMigrations(t.Context(), db); err != nil {
+		t.Fatalf("delete migrations failed: %s", err)
+	}
+	if err := m.deleteMigrations(t.Context(), db, []string{}...); err != nil {
+		t.Fatalf("delete migrations failed: %s", err)
+	}
+	if len(db.calls) > 0 {
+		t.Errorf("expected no queries, got %q", db.calls)
+	}
+}
+
+func TestDeleteMigrations(t *testing.T) {
+	tests := []struct {
+		idType string
+		want   string
+	}{
+		{"uuid", "DELETE FROM migrations WHERE id = ANY($1::text[]::uuid[])"},
+		{"bigint", "DELETE FROM migrations WHERE id = ANY($1::text[]::bigint[])"},
+	}
+	for _, test := range tests {
+		db := new(recordingDB)
+		m := newVermig(WithDB(db))
+		m.idType = test.idType
+		if err := m.deleteMigrations(t.Context(), db, "1", "2"); err != nil {
+			t.Fatalf("delete migrations failed: %s", err)
+		}
+		if len(db.calls) != 1 {
+			t.Fatalf("expected one query, got %q", db.calls)
+		}
+		if db.calls[0] != test.want {
+			t.Errorf("sql: got %q, want %q", db.calls[0], test.want)
+		}
+		if want := []any{[]string{"1", "2"}}; !reflect.DeepEqual(db.args[0], want) {
+			t.Errorf("args: got %v, want %v", db.args[0], want)
+		}
+	}
+}
+
+func TestIDTypeFromTable(t *testing.T) {
+	for _, serialID := range []bool{true, false} {
+		t.Run(
+			fmt.Sprintf("serial id %t", serialID), func(t *testing.T) {
+				db := testDB(t)
+				files := testFiles("1.0.0", "1.1.0")
+				setup := testVermig(t, db, files, WithSerialID(serialID))
+				if err := setup.Migrate(t.Context(), "1.1.0"); err != nil {
+					t.Fatalf("migrate failed: %s", err)
+				}
+				m := testVermig(t, db, files, WithSerialID(!serialID), WithAllowDowngrade(true))
+				if want := map[bool]string{true: "bigint", false: "uuid"}[serialID]; m.idType != want {
+					t.Errorf("id type: got %q, want %q", m.idType, want)
+				}
+				if err := m.Migrate(t.Context(), "1.0.0"); err != nil {
+					t.Fatalf("downgrade failed: %s", err)
+				}
+				if err := m.Reset(t.Context()); err != nil {
+					t.Fatalf("reset failed: %s", err)
+				}
+			},
+		)
+	}
+}
+
+func testDB(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("VERMIG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("VERMIG_TEST_DSN is not set")
+	}
+	admin, connectErr := pgxpool.New(t.Context(), dsn)
+	if connectErr != nil {
+		t.Fatalf("connect failed: %s", connectErr)
+	}
+	t.Cleanup(admin.Close)
+	schema := fmt.Sprintf("vermig_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(t.Context(), "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema failed: %s", err)
+	}
+	t.Cleanup(
+		func() {
+			if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+				t.Errorf("drop schema failed: %s", err)
+			}
+		},
+	)
+	config, parseConfigErr := pgxpool.ParseConfig(dsn)
+	if parseConfigErr != nil {
+		t.Fatalf("parse dsn failed: %s", parseConfigErr)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	db, createPoolErr := pgxpool.NewWithConfig(t.Context(), config)
+	if createPoolErr != nil {
+		t.Fatalf("connect failed: %s", createPoolErr)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+func testFiles(versions ...string) fstest.MapFS {
+	files := make(fstest.MapFS, 2*len(versions))
+	for _, version := range versions {
+		table := "t_" + strings.NewReplacer(".", "_", "-", "_").Replace(version)
+		files[version+"_create-"+table+"_up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE " + table + " (id int);")}
+		files[version+"_create-"+table+"_down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE " + table + ";")}
+	}
+	return files
+}
+
+func testVermig(t testing.TB, db DB, files fstest.MapFS, options ...Option) *Vermig {
+	t.Helper()
+	m, createMigratorErr := New(t.Context(), append([]Option{WithDB(db), WithFS(files)}, options...)...)
+	if createMigratorErr != nil {
+		t.Fatalf("create migrator failed: %s", createMigratorErr)
+	}
+	return m
+}
+
+func TestNewConcurrent(t *testing.T) {
+	db := testDB(t)
+	const n = 8
+	errs := make(chan error, n)
+	for range n {
+		go func() {
+			_, err := New(t.Context(), WithDB(db), WithFS(testFiles("1.0.0")), WithVersionTable(true))
+			errs <- err
+		}()
+	}
+	for range n {
+		if err := <-errs; err != nil {
+			t.Errorf("create migrator failed: %s", err)
+		}
+	}
+	var tables int
+	if err := db.QueryRow(t.Context(), "SELECT count(*) FROM pg_tables WHERE schemaname = current_schema()").Scan(&tables); err != nil {
+		t.Fatalf("count tables failed: %s", err)
+	}
+	if tables != 2 {
+		t.Errorf("expected the migrations and version tables, got %d tables", tables)
+	}
+}
@@ -0,0 +1,168 @@
+package vermigfs
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+type mapFS map[string][]byte
+
+func (m mapFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, exists := m[name]; exists {
+		return &file{info: fileInfo{name: path.Base(name), size: int64(len(data))}, data: data}, nil
+	}
+	entries, isDir := m.entries(name)
+	if !isDir {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &dir{info: fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (m mapFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	data, exists := m[name]
+	if !exists {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (m mapFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries, isDir := m.entries(name)
+	if !isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+func (m mapFS) entries(name string) ([]fs.DirEntry, bool) {
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	children := make(map[string]fs.DirEntry)
+	for key, data := range m {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if child, _, nested := strings.Cut(rest, "/"); nested {
+			children[child] = fs.FileInfoToDirEntry(fileInfo{name: child, dir: true})
+		} else {
+			children[child] = fs.FileInfoToDirEntry(fileInfo{name: child, size: int64(len(data))})
+		}
+	}
+	if len(children) == 0 && name != "." {
+		return nil, false
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, entry := range children {
+		entries = append(entries, entry)
+	}
+	sort.Slice(
+		entries, func(i, j int) bool {
+			return entries[i].Name() < entries[j].Name()
+		},
+	)
+	return entries, true
+}
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i fileInfo) Name() string {
+	return i.name
+}
+
+func (i fileInfo) Size() int64 {
+	return i.size
+}
+
+func (i fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (i fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i fileInfo) IsDir() bool {
+	return i.dir
+}
+
+func (i fileInfo) Sys() any {
+	return nil
+}
+
+type file struct {
+	info   fileInfo
+	data   []byte
+	offset int
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *file) Read(b []byte) (int, error) {
+	if f.offset >= len(f.data) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[f.offset:])
+	f.offset += n
+	return n, nil
+}
+
+func (f *file) Close() error {
+	return nil
+}
+
+type dir struct {
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *dir) Close() error {
+	return nil
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.offset += len(rest)
+	return rest, nil
+}
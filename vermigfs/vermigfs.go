@@ -0,0 +1,75 @@
+package vermigfs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+type Store interface {
+	List(ctx context.Context, prefix string) ([]string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+const defaultConcurrency = 8
+
+func Load(ctx context.Context, store Store, prefix string, concurrency int) (fs.FS, error) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	prefix = strings.Trim(prefix, "/")
+	keys, listErr := store.List(ctx, prefix)
+	if listErr != nil {
+		return nil, fmt.Errorf("list %s failed: %w", prefix, listErr)
+	}
+	loadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	files := make(mapFS, len(keys))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	semaphore := make(chan struct{}, concurrency)
+schedule:
+	for _, key := range keys {
+		name := strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/")
+		if name == "" || strings.HasSuffix(name, "/") || !fs.ValidPath(name) {
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-loadCtx.Done():
+			break schedule
+		}
+		if loadCtx.Err() != nil {
+			<-semaphore
+			break schedule
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			data, getErr := store.Get(loadCtx, key)
+			mu.Lock()
+			defer mu.Unlock()
+			if getErr != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("get %s failed: %w", key, getErr)
+					cancel()
+				}
+				return
+			}
+			files[path.Clean(name)] = data
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("load %s failed: %w", prefix, err)
+	}
+	return files, nil
+}
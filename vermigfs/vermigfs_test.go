@@ -0,0 +1,92 @@
+package vermigfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+type fakeStore struct {
+	objects map[string]string
+	failing string
+	gets    atomic.Int32
+}
+
+func (s *fakeStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *fakeStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.gets.Add(1)
+	if key == s.failing {
+		return nil, errors.New("access denied")
+	}
+	return []byte(s.objects[key]), nil
+}
+
+func TestLoad(t *testing.T) {
+	store := &fakeStore{
+		objects: map[string]string{
+			"db/migrations/1.0.0_init_up.sql":          "CREATE TABLE a (id int);",
+			"db/migrations/1.0.0_init_down.sql":        "DROP TABLE a;",
+			"db/migrations/users/1.1.0_users_up.sql":   "CREATE TABLE users (id int);",
+			"db/migrations/users/":                     "",
+			"db/other/1.0.0_ignored_up.sql":            "SELECT 1;",
+			"db/migrations/users/admin/1.2.0_a_up.sql": "SELECT 1;",
+		},
+	}
+	files, loadErr := Load(t.Context(), store, "/db/migrations/", 2)
+	if loadErr != nil {
+		t.Fatalf("load failed: %s", loadErr)
+	}
+	if err := fstest.TestFS(
+		files, "1.0.0_init_up.sql", "1.0.0_init_down.sql", "users/1.1.0_users_up.sql", "users/admin/1.2.0_a_up.sql",
+	); err != nil {
+		t.Fatal(err)
+	}
+	data, readErr := fs.ReadFile(files, "users/1.1.0_users_up.sql")
+	if readErr != nil {
+		t.Fatalf("read failed: %s", readErr)
+	}
+	if string(data) != "CREATE TABLE users (id int);" {
+		t.Errorf("got %q", data)
+	}
+	if _, err := fs.Stat(files, "1.0.0_ignored_up.sql"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestLoadStopsOnError(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{"m/a_up.sql": "", "m/b_up.sql": "", "m/c_up.sql": ""}}
+	store.failing = "m/a_up.sql"
+	if _, err := Load(t.Context(), store, "m", 1); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("got %v, want the get error", err)
+	}
+	if gets := store.gets.Load(); gets != 1 {
+		t.Errorf("got %d gets after the first error, want 1", gets)
+	}
+}
+
+func TestLoadCancelled(t *testing.T) {
+	store := &fakeStore{objects: map[string]string{"m/a_up.sql": "", "m/b_up.sql": ""}}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := Load(ctx, store, "m", 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+	if gets := store.gets.Load(); gets != 0 {
+		t.Errorf("got %d gets after cancel, want 0", gets)
+	}
+}
@@ -0,0 +1,68 @@
+package vermigtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/daarxwalker/vermig"
+)
+
+func New(t testing.TB, options ...vermig.Option) *vermig.Vermig {
+	t.Helper()
+	m, createMigratorErr := vermig.New(t.Context(), options...)
+	if createMigratorErr != nil {
+		t.Fatalf("create migrator failed: %s", createMigratorErr)
+	}
+	return m
+}
+
+func MigrateLatest(t testing.TB, m *vermig.Vermig) {
+	t.Helper()
+	if err := m.MigrateLatest(t.Context()); err != nil {
+		t.Fatalf("migrate latest failed: %s", err)
+	}
+}
+
+func Migrate(t testing.TB, m *vermig.Vermig, version string) {
+	t.Helper()
+	if err := m.Migrate(t.Context(), version); err != nil {
+		t.Fatalf("migrate to %s failed: %s", version, err)
+	}
+}
+
+func AssertAppliedVersion(t testing.TB, m *vermig.Vermig, version string) {
+	t.Helper()
+	current, currentVersionErr := m.CurrentVersion(t.Context())
+	if currentVersionErr != nil {
+		t.Fatalf("get current version failed: %s", currentVersionErr)
+	}
+	expected, parseVersionErr := m.ParseVersion(version)
+	if parseVersionErr != nil {
+		t.Fatalf("parse version %s failed: %s", version, parseVersionErr)
+	}
+	if !current.Equal(expected) {
+		t.Errorf("applied version: got %s, want %s", current, expected)
+	}
+}
+
+func AssertTableExists(t testing.TB, db vermig.DB, name string) {
+	t.Helper()
+	var exists bool
+	if err := db.QueryRow(t.Context(), "SELECT to_regclass($1) IS NOT NULL", name).Scan(&exists); err != nil {
+		t.Fatalf("check table %s existence failed: %s", name, err)
+	}
+	if !exists {
+		t.Errorf("table %s does not exist", name)
+	}
+}
+
+func AssertSnapshot(t testing.TB, m *vermig.Vermig, baseSQL, version, expected string) {
+	t.Helper()
+	result, applyErr := m.ApplyToSnapshot(t.Context(), baseSQL, version)
+	if applyErr != nil {
+		t.Fatalf("apply to snapshot failed: %s", applyErr)
+	}
+	if strings.TrimSpace(result) != strings.TrimSpace(expected) {
+		t.Errorf("snapshot at %s:\ngot:\n%s\nwant:\n%s", version, result, expected)
+	}
+}
@@ -0,0 +1,162 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+func parseFileVersion(raw string) (*semver.Version, int, error) {
+	raw = strings.TrimSpace(raw)
+	coreEnd := strings.IndexAny(raw, "-+")
+	if coreEnd < 0 {
+		coreEnd = len(raw)
+	}
+	parts := strings.Split(raw[:coreEnd], ".")
+	if len(parts) != 4 {
+		version, parseErr := parseVersion(raw)
+		return version, 0, parseErr
+	}
+	step, parseStepErr := strconv.Atoi(parts[3])
+	if parseStepErr != nil || step < 0 {
+		return nil, 0, fmt.Errorf("invalid step %q in version %s", parts[3], raw)
+	}
+	version, parseErr := parseVersion(strings.Join(parts[:3], ".") + raw[coreEnd:])
+	return version, step, parseErr
+}
+
+func parseVersion(raw string) (*semver.Version, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "v") || strings.HasPrefix(raw, "V") {
+		raw = raw[1:]
+	}
+	return semver.NewVersion(raw)
+}
+
+func (m *Vermig) ParseVersion(raw string) (*semver.Version, error) {
+	if m.ordering == OrderingFilename && strings.Trim(strings.TrimSpace(raw), "0123456789") == "" {
+		return filenameVersion(raw)
+	}
+	return parseVersion(raw)
+}
+
+func (m *Vermig) resolveTargetVersion(raw string) (*semver.Version, error) {
+	if m.ordering == OrderingFilename && strings.Trim(strings.TrimSpace(raw), "0123456789") == "" {
+		return filenameVersion(raw)
+	}
+	pv, parseErr := parseVersion(raw)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	core := strings.TrimLeft(strings.TrimSpace(raw), "vV")
+	if end := strings.IndexAny(core, "-+"); end >= 0 {
+		return pv, nil
+	}
+	components := len(strings.Split(core, "."))
+	if components >= 3 {
+		return pv, nil
+	}
+	resolved := pv
+	for _, file := range m.files {
+		if file.Version.Major() != pv.Major() || (components == 2 && file.Version.Minor() != pv.Minor()) {
+			continue
+		}
+		if m.pinnedVersion != nil && file.Version.GreaterThan(m.pinnedVersion) {
+			continue
+		}
+		if file.Version.GreaterThan(resolved) {
+			resolved = file.Version
+		}
+	}
+	return resolved, nil
+}
+
+func (m *Vermig) CurrentVersion(ctx context.Context) (*semver.Version, error) {
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return nil, fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	return highestVersion(
+		migrations, func(Migration) bool {
+			return true
+		},
+	)
+}
+
+func (m *Vermig) NextVersion(ctx context.Context, scope string) (*semver.Version, error) {
+	m, collectFilesErr := m.collectFiles()
+	if collectFilesErr != nil {
+		return nil, fmt.Errorf("collect migrations failed: %w", collectFilesErr)
+	}
+	migrations, findErr := m.findAllMigrations(ctx, m.db)
+	if findErr != nil {
+		return nil, fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	current, currentVersionErr := highestVersion(
+		migrations, func(migration Migration) bool {
+			return migration.Scope == scope
+		},
+	)
+	if currentVersionErr != nil {
+		return nil, fmt.Errorf("get current version failed: %w", currentVersionErr)
+	}
+	applied := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		applied[migration.key()] = struct{}{}
+	}
+	var next *semver.Version
+	for _, file := range m.files {
+		if file.Scope != scope || !file.Version.GreaterThan(current) {
+			continue
+		}
+		if _, exists := applied[file.key()]; exists {
+			continue
+		}
+		if next == nil || file.Version.LessThan(next) {
+			next = file.Version
+		}
+	}
+	if next == nil {
+		return nil, ErrNoPendingMigrations
+	}
+	return next, nil
+}
+
+func versionCore(version *semver.Version) [3]int64 {
+	return [3]int64{version.Major(), version.Minor(), version.Patch()}
+}
+
+func compareVersion(a, b [3]int64) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func highestVersion(migrations []Migration, matches func(Migration) bool) (*semver.Version, error) {
+	var current *semver.Version
+	for _, migration := range migrations {
+		if !matches(migration) {
+			continue
+		}
+		version, parseErr := parseVersion(migration.Version)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parse version failed: %w", parseErr)
+		}
+		if current == nil || version.GreaterThan(current) {
+			current = version
+		}
+	}
+	if current == nil {
+		return semver.NewVersion("0.0.0")
+	}
+	return current, nil
+}
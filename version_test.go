@@ -0,0 +1,77 @@
+package vermig
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Masterminds/semver"
+)
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.2.4", "1.2.3", 1},
+		{"1.2.9", "1.3.0", -1},
+		{"1.3.0", "1.2.9", 1},
+		{"1.9.9", "2.0.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.2.0-rc.1", "1.2.0", 0},
+		{"1.2.0-rc.1", "1.2.0-rc.2", 0},
+		{"1.2.0-rc.1", "1.1.9", 1},
+	}
+	for _, test := range tests {
+		got := compareVersion(versionCore(semver.MustParse(test.a)), versionCore(semver.MustParse(test.b)))
+		if got != test.want {
+			t.Errorf("compare %s with %s: got %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestFilterHigherVersions(t *testing.T) {
+	rows := []string{
+		"1.1.9", "1.2.0-rc.1", "1.2.0-rc.2", "1.2.0", "1.2.1", "1.3.0", "1.10.0", "2.0.0-alpha", "2.0.0",
+	}
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"1.2.0", []string{"1.2.1", "1.3.0", "1.10.0", "2.0.0-alpha", "2.0.0"}},
+		{"1.2.0-rc.1", []string{"1.2.0-rc.2", "1.2.0", "1.2.1", "1.3.0", "1.10.0", "2.0.0-alpha", "2.0.0"}},
+		{"1.2", []string{"1.2.1", "1.3.0", "1.10.0", "2.0.0-alpha", "2.0.0"}},
+		{"1.9.0", []string{"1.10.0", "2.0.0-alpha", "2.0.0"}},
+		{"2.0.0-alpha", []string{"2.0.0"}},
+		{"2.0.0", nil},
+	}
+	for _, test := range tests {
+		target := semver.MustParse(test.target)
+		var prefiltered []Migration
+		for _, version := range rows {
+			if compareVersion(versionCore(semver.MustParse(version)), versionCore(target)) >= 0 {
+				prefiltered = append(prefiltered, Migration{Version: version})
+			}
+		}
+		higher, filterErr := filterHigherVersions(prefiltered, target)
+		if filterErr != nil {
+			t.Fatalf("filter higher versions failed: %s", filterErr)
+		}
+		var got []string
+		for _, migration := range higher {
+			got = append(got, migration.Version)
+			if !semver.MustParse(migration.Version).GreaterThan(target) {
+				t.Errorf("target %s: %s is not above it", test.target, migration.Version)
+			}
+		}
+		for _, version := range rows {
+			if semver.MustParse(version).GreaterThan(target) && !slices.Contains(got, version) {
+				t.Errorf("target %s: %s is above it but was filtered out", test.target, version)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("target %s: got %v, want %v", test.target, got, test.want)
+		}
+	}
+}
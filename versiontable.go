@@ -0,0 +1,58 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (m *Vermig) versionTableName() string {
+	if schema := m.tableSchema(); schema != "public" {
+		return pgx.Identifier{schema, "schema_version"}.Sanitize()
+	}
+	return "schema_version"
+}
+
+func (m *Vermig) createVersionTable(ctx context.Context, db DB) error {
+	if _, err := db.Exec(
+		ctx, `CREATE TABLE IF NOT EXISTS `+m.versionTableName()+` (
+		id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+		version VARCHAR(255) NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	); err != nil {
+		return fmt.Errorf("create version table failed: %w", err)
+	}
+	return nil
+}
+
+func (m *Vermig) syncVersionTable(ctx context.Context, tx pgx.Tx) error {
+	if !m.versionTable {
+		return nil
+	}
+	migrations, findErr := m.findAllMigrations(ctx, tx)
+	if findErr != nil {
+		return fmt.Errorf("find all migrations failed: %w", findErr)
+	}
+	current, currentVersionErr := highestVersion(
+		migrations, func(Migration) bool {
+			return true
+		},
+	)
+	if currentVersionErr != nil {
+		return fmt.Errorf("get current version failed: %w", currentVersionErr)
+	}
+	sql, args, createSqlErr := m.statementBuilder.Insert(m.versionTableName()).
+		Columns("id", "version").
+		Values(true, current.String()).
+		Suffix("ON CONFLICT (id) DO UPDATE SET version = EXCLUDED.version, updated_at = CURRENT_TIMESTAMP").
+		ToSql()
+	if createSqlErr != nil {
+		return fmt.Errorf("create sync version table sql failed: %w", createSqlErr)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("update version table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package vermig
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type forceOutsideWindowKey struct{}
+
+func ForceOutsideWindow(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceOutsideWindowKey{}, true)
+}
+
+func (m *Vermig) checkWindow(ctx context.Context) error {
+	if m.allowedWindow == nil {
+		return nil
+	}
+	now := time.Now()
+	if m.allowedWindow(now) {
+		return nil
+	}
+	if forced, _ := ctx.Value(forceOutsideWindowKey{}).(bool); forced {
+		m.logf(ctx, "⚠️ migrating outside the allowed window (forced)")
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOutsideWindow, now.Format(time.RFC3339))
+}